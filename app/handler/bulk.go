@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/anggara-26/pomodoro-backend.git/app/model"
+	"github.com/go-playground/validator"
+	"github.com/gofiber/fiber/v2"
+)
+
+// @Summary        Bulk Update Tasks
+// @Description    Applies one operation to up to 100 of the caller's tasks: set_status, delete, restore, set_tags or reschedule. The result of every ID is reported separately, a failure on one task does not stop the others. set_status follows the same transitions as updating a single task
+// @Tags           Task
+// @Accept         json
+// @Produce        json
+// @Param          bulk body model.BulkTaskDTO true "Operation"
+// @Success        200 {object} Response{data=[]model.BulkResult}
+// @Security       BearerAuth
+// @Router         /api/v1/tasks/bulk [post]
+func (h *Handler) BulkUpdateTasks(c *fiber.Ctx) error {
+	b := new(model.BulkTaskDTO)
+	if err := c.BodyParser(b); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+	}
+	if b.Tags != nil {
+		b.Tags = model.NormalizeTags(b.Tags)
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(b); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+	}
+
+	var missing string
+	switch {
+	case b.Operation == model.BulkSetStatus && b.Status == "":
+		missing = "status"
+	case b.Operation == model.BulkSetTags && b.Tags == nil:
+		missing = "tags"
+	case b.Operation == model.BulkReschedule && b.AssignedAt == nil:
+		missing = "assigned_at"
+	}
+	if missing != "" {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: "Operation " + string(b.Operation) + " requires " + missing,
+			Code:    http.StatusBadRequest,
+		})
+	}
+
+	results, err := h.models.Tasks.Bulk(c.Context(), currentUser(c).ID, *b, time.Now().UTC())
+	if errors.Is(err, model.ErrConflict) {
+		return c.Status(http.StatusConflict).JSON(Response{
+			Message: "Tasks were modified concurrently, please retry",
+			Code:    http.StatusConflict,
+		})
+	}
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(Response{
+			Message: "Failed to update tasks",
+			Code:    http.StatusInternalServerError,
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(Response{
+		Message: "Bulk operation applied",
+		Code:    http.StatusOK,
+		Data:    results,
+		Total:   int64(len(results)),
+	})
+}
@@ -0,0 +1,118 @@
+package handler_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/anggara-26/pomodoro-backend.git/app/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestBulkUpdateTasks(t *testing.T) {
+	var (
+		ok          = ""
+		notFound    = model.ErrNotFound.Error()
+		illegalMove = model.ErrInvalidTransition.Error()
+		assignedAt  = time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+		order       = []string{"pending", "in progress", "trashed", "foreign", "unknown"}
+	)
+
+	tests := []struct {
+		name string
+		body map[string]any
+		// want is the error expected for each task, in order.
+		want []string
+	}{
+		{"set status in progress", map[string]any{"operation": model.BulkSetStatus, "status": model.TaskInProgress}, []string{ok, ok, notFound, notFound, notFound}},
+		{"illegal set status", map[string]any{"operation": model.BulkSetStatus, "status": model.TaskCompleted}, []string{illegalMove, ok, notFound, notFound, notFound}},
+		{"delete", map[string]any{"operation": model.BulkDelete}, []string{ok, ok, notFound, notFound, notFound}},
+		{"restore", map[string]any{"operation": model.BulkRestore}, []string{notFound, notFound, ok, notFound, notFound}},
+		{"set tags", map[string]any{"operation": model.BulkSetTags, "tags": []string{"thesis"}}, []string{ok, ok, notFound, notFound, notFound}},
+		{"reschedule", map[string]any{"operation": model.BulkReschedule, "assigned_at": assignedAt}, []string{ok, ok, notFound, notFound, notFound}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t)
+			user := s.signUp(t, "user")
+			other := s.signUp(t, "other")
+
+			ids := map[string]string{
+				"pending":     s.createTask(t, user, "Pending"),
+				"in progress": s.createTask(t, user, "In progress"),
+				"trashed":     s.createTask(t, user, "Trashed"),
+				"foreign":     s.createTask(t, other, "Foreign"),
+				"unknown":     primitive.NewObjectID().Hex(),
+			}
+			s.do(t, user, "PUT", "/api/v1/tasks/"+ids["in progress"], map[string]any{"status": model.TaskInProgress})
+			s.do(t, user, "DELETE", "/api/v1/tasks/"+ids["trashed"], nil)
+
+			body := map[string]any{}
+			for key, value := range tt.body {
+				body[key] = value
+			}
+			var requested []string
+			for _, name := range order {
+				requested = append(requested, ids[name])
+			}
+			body["ids"] = requested
+
+			res := s.do(t, user, "POST", "/api/v1/tasks/bulk", body)
+			if res.Status != http.StatusOK {
+				t.Fatalf("status = %d (%s), want %d", res.Status, res.Message, http.StatusOK)
+			}
+			var results []model.BulkResult
+			res.decode(t, &results)
+			if len(results) != len(order) {
+				t.Fatalf("results = %+v, want one per ID", results)
+			}
+			for i, name := range order {
+				result := results[i]
+				if result.ID.Hex() != ids[name] || result.OK != (tt.want[i] == ok) || result.Error != tt.want[i] {
+					t.Errorf("%s: result = %+v, want error %q", name, result, tt.want[i])
+				}
+			}
+
+			var foreign model.Task
+			s.do(t, other, "GET", "/api/v1/tasks/"+ids["foreign"], nil).decode(t, &foreign)
+			if foreign.Status != model.TaskPending || len(foreign.Tags) != 0 || foreign.AssignedAt.Equal(assignedAt) {
+				t.Errorf("foreign task = %+v, want it untouched", foreign)
+			}
+		})
+	}
+}
+
+func TestBulkUpdateTasksValidation(t *testing.T) {
+	s := newTestServer(t)
+	user := s.signUp(t, "user")
+	id := s.createTask(t, user, "Task")
+
+	tests := []struct {
+		name string
+		body map[string]any
+	}{
+		{"no ids", map[string]any{"ids": []string{}, "operation": model.BulkDelete}},
+		{"duplicate ids", map[string]any{"ids": []string{id, id}, "operation": model.BulkDelete}},
+		{"unknown operation", map[string]any{"ids": []string{id}, "operation": "archive"}},
+		{"set status without a status", map[string]any{"ids": []string{id}, "operation": model.BulkSetStatus}},
+		{"set status to deleted", map[string]any{"ids": []string{id}, "operation": model.BulkSetStatus, "status": model.TaskDeleted}},
+		{"set tags without tags", map[string]any{"ids": []string{id}, "operation": model.BulkSetTags}},
+		{"reschedule without a date", map[string]any{"ids": []string{id}, "operation": model.BulkReschedule}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := s.do(t, user, "POST", "/api/v1/tasks/bulk", tt.body)
+			if res.Status != http.StatusBadRequest {
+				t.Errorf("status = %d (%s), want %d", res.Status, res.Message, http.StatusBadRequest)
+			}
+		})
+	}
+
+	var task model.Task
+	s.do(t, user, "GET", "/api/v1/tasks/"+id, nil).decode(t, &task)
+	if task.Status != model.TaskPending {
+		t.Errorf("task status = %s, want it untouched", task.Status)
+	}
+}
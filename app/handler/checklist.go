@@ -0,0 +1,233 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/anggara-26/pomodoro-backend.git/app/model"
+	"github.com/go-playground/validator"
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// @Summary        Add Checklist Item
+// @Description    Appends an item to the checklist of a task, item estimates add up to the estimate of the task
+// @Tags           Task
+// @Accept         json
+// @Produce        json
+// @Param          id path string true "Task ID"
+// @Param          item body model.AddChecklistItemDTO true "Checklist Item"
+// @Success        201 {object} Response{data=model.Task}
+// @Security       BearerAuth
+// @Router         /api/v1/tasks/{id}/checklist [post]
+func (h *Handler) AddChecklistItem(c *fiber.Ctx) error {
+	objectID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: "Invalid ID",
+			Code:    http.StatusBadRequest,
+		})
+	}
+
+	b := new(model.AddChecklistItemDTO)
+	if err := c.BodyParser(b); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(b); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+	}
+
+	userID := currentUser(c).ID
+	task, err := h.models.Tasks.FindByID(c.Context(), userID, objectID)
+	if errors.Is(err, model.ErrNotFound) || err == nil && task.Status == model.TaskDeleted {
+		return c.Status(http.StatusNotFound).JSON(Response{
+			Message: "Task not found",
+			Code:    http.StatusNotFound,
+		})
+	}
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(Response{
+			Message: "Failed to get task",
+			Code:    http.StatusInternalServerError,
+		})
+	}
+	if len(task.Checklist) >= model.MaxChecklistItems {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: "Checklist is full",
+			Code:    http.StatusBadRequest,
+		})
+	}
+
+	item := model.ChecklistItem{
+		ID:                 primitive.NewObjectID(),
+		Title:              b.Title,
+		EstimatedPomodoros: b.EstimatedPomodoros,
+	}
+
+	task, err = h.models.Tasks.AddChecklistItem(c.Context(), userID, objectID, item, time.Now().UTC())
+	if errors.Is(err, model.ErrNotFound) {
+		return c.Status(http.StatusNotFound).JSON(Response{
+			Message: "Task not found",
+			Code:    http.StatusNotFound,
+		})
+	}
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(Response{
+			Message: "Failed to add checklist item",
+			Code:    http.StatusInternalServerError,
+		})
+	}
+
+	return c.Status(http.StatusCreated).JSON(Response{
+		Message: "Checklist item added successfully",
+		Code:    http.StatusCreated,
+		Data:    task,
+	})
+}
+
+// @Summary        Reorder Checklist
+// @Description    Rearranges the checklist of a task, item_ids must list every item exactly once
+// @Tags           Task
+// @Accept         json
+// @Produce        json
+// @Param          id path string true "Task ID"
+// @Param          order body model.ReorderChecklistDTO true "Checklist Order"
+// @Success        200 {object} Response{data=model.Task}
+// @Security       BearerAuth
+// @Router         /api/v1/tasks/{id}/checklist/order [put]
+func (h *Handler) ReorderChecklist(c *fiber.Ctx) error {
+	objectID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: "Invalid ID",
+			Code:    http.StatusBadRequest,
+		})
+	}
+
+	b := new(model.ReorderChecklistDTO)
+	if err := c.BodyParser(b); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+	}
+
+	userID := currentUser(c).ID
+	task, err := h.models.Tasks.FindByID(c.Context(), userID, objectID)
+	if errors.Is(err, model.ErrNotFound) || err == nil && task.Status == model.TaskDeleted {
+		return c.Status(http.StatusNotFound).JSON(Response{
+			Message: "Task not found",
+			Code:    http.StatusNotFound,
+		})
+	}
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(Response{
+			Message: "Failed to get task",
+			Code:    http.StatusInternalServerError,
+		})
+	}
+	if !task.IsChecklistOrder(b.ItemIDs) {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: "Item IDs must list every checklist item once",
+			Code:    http.StatusBadRequest,
+		})
+	}
+
+	task, err = h.models.Tasks.ReorderChecklist(c.Context(), userID, objectID, b.ItemIDs, time.Now().UTC())
+	if errors.Is(err, model.ErrNotFound) {
+		return c.Status(http.StatusNotFound).JSON(Response{
+			Message: "Task not found",
+			Code:    http.StatusNotFound,
+		})
+	}
+	if errors.Is(err, model.ErrConflict) {
+		return c.Status(http.StatusConflict).JSON(Response{
+			Message: "Checklist was changed meanwhile",
+			Code:    http.StatusConflict,
+		})
+	}
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(Response{
+			Message: "Failed to reorder checklist",
+			Code:    http.StatusInternalServerError,
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(Response{
+		Message: "Checklist reordered successfully",
+		Code:    http.StatusOK,
+		Data:    task,
+	})
+}
+
+// @Summary        Check Checklist Item
+// @Description    Marks a checklist item as done
+// @Tags           Task
+// @Produce        json
+// @Param          id path string true "Task ID"
+// @Param          itemId path string true "Checklist Item ID"
+// @Success        200 {object} Response{data=model.Task}
+// @Security       BearerAuth
+// @Router         /api/v1/tasks/{id}/checklist/{itemId}/check [post]
+func (h *Handler) CheckChecklistItem(c *fiber.Ctx) error {
+	return h.setChecklistItemDone(c, true)
+}
+
+// @Summary        Uncheck Checklist Item
+// @Description    Marks a checklist item as not done
+// @Tags           Task
+// @Produce        json
+// @Param          id path string true "Task ID"
+// @Param          itemId path string true "Checklist Item ID"
+// @Success        200 {object} Response{data=model.Task}
+// @Security       BearerAuth
+// @Router         /api/v1/tasks/{id}/checklist/{itemId}/uncheck [post]
+func (h *Handler) UncheckChecklistItem(c *fiber.Ctx) error {
+	return h.setChecklistItemDone(c, false)
+}
+
+func (h *Handler) setChecklistItemDone(c *fiber.Ctx, done bool) error {
+	objectID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: "Invalid ID",
+			Code:    http.StatusBadRequest,
+		})
+	}
+	itemID, err := primitive.ObjectIDFromHex(c.Params("itemId"))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: "Invalid item ID",
+			Code:    http.StatusBadRequest,
+		})
+	}
+
+	task, err := h.models.Tasks.SetChecklistItemDone(c.Context(), currentUser(c).ID, objectID, itemID, done, time.Now().UTC())
+	if errors.Is(err, model.ErrNotFound) {
+		return c.Status(http.StatusNotFound).JSON(Response{
+			Message: "Checklist item not found",
+			Code:    http.StatusNotFound,
+		})
+	}
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(Response{
+			Message: "Failed to update checklist item",
+			Code:    http.StatusInternalServerError,
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(Response{
+		Message: "Checklist item updated successfully",
+		Code:    http.StatusOK,
+		Data:    task,
+	})
+}
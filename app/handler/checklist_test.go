@@ -0,0 +1,101 @@
+package handler_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/anggara-26/pomodoro-backend.git/app/model"
+)
+
+// addChecklistItem adds an item to the checklist of task id and returns the updated task.
+func (s *testServer) addChecklistItem(t *testing.T, user testUser, id, title string, estimate int) model.Task {
+	t.Helper()
+
+	res := s.do(t, user, "POST", "/api/v1/tasks/"+id+"/checklist", map[string]any{"title": title, "estimated_pomodoros": estimate})
+	if res.Status != http.StatusCreated {
+		t.Fatalf("add checklist item: status %d: %s", res.Status, res.Message)
+	}
+	var task model.Task
+	res.decode(t, &task)
+
+	return task
+}
+
+func TestChecklistOfTrashedTask(t *testing.T) {
+	s := newTestServer(t)
+	user := s.signUp(t, "user")
+	id := s.createTask(t, user, "Task")
+	task := s.addChecklistItem(t, user, id, "Outline", 1)
+	itemID := task.Checklist[0].ID.Hex()
+	if res := s.do(t, user, "DELETE", "/api/v1/tasks/"+id, nil); res.Status != http.StatusOK {
+		t.Fatalf("delete: status %d: %s", res.Status, res.Message)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   any
+	}{
+		{"add", "POST", "/api/v1/tasks/" + id + "/checklist", map[string]any{"title": "Draft"}},
+		{"reorder", "PUT", "/api/v1/tasks/" + id + "/checklist/order", map[string]any{"item_ids": []string{itemID}}},
+		{"check", "POST", "/api/v1/tasks/" + id + "/checklist/" + itemID + "/check", nil},
+		{"uncheck", "POST", "/api/v1/tasks/" + id + "/checklist/" + itemID + "/uncheck", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := s.do(t, user, tt.method, tt.path, tt.body)
+			if res.Status != http.StatusNotFound {
+				t.Errorf("status = %d (%s), want %d", res.Status, res.Message, http.StatusNotFound)
+			}
+		})
+	}
+
+	var trashed model.Task
+	s.do(t, user, "GET", "/api/v1/tasks/"+id, nil).decode(t, &trashed)
+	if len(trashed.Checklist) != 1 || trashed.Checklist[0].Done {
+		t.Errorf("checklist = %+v, want it untouched", trashed.Checklist)
+	}
+}
+
+func TestChecklistEstimateRollup(t *testing.T) {
+	tests := []struct {
+		name      string
+		estimates []int
+		method    string
+		want      int16
+	}{
+		{"put without checklist", nil, "PUT", 7},
+		{"patch without checklist", nil, "PATCH", 7},
+		{"put with unestimated items", []int{0, 0}, "PUT", 7},
+		{"put with estimated items", []int{1, 2}, "PUT", 3},
+		{"patch with estimated items", []int{1, 2}, "PATCH", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t)
+			user := s.signUp(t, "user")
+			id := s.createTask(t, user, "Task")
+			for _, estimate := range tt.estimates {
+				s.addChecklistItem(t, user, id, "Step", estimate)
+			}
+
+			if res := s.do(t, user, tt.method, "/api/v1/tasks/"+id, map[string]any{"estimated_pomodoros": 7}); res.Status != http.StatusOK {
+				t.Fatalf("status = %d (%s), want %d", res.Status, res.Message, http.StatusOK)
+			}
+			var task model.Task
+			s.do(t, user, "GET", "/api/v1/tasks/"+id, nil).decode(t, &task)
+			if task.EstimatedPomodoros != tt.want {
+				t.Fatalf("estimated pomodoros = %d, want %d", task.EstimatedPomodoros, tt.want)
+			}
+
+			// Adding an item keeps an estimate set on a task without estimated items.
+			task = s.addChecklistItem(t, user, id, "Unestimated", 0)
+			if task.EstimatedPomodoros != tt.want {
+				t.Errorf("estimated pomodoros after adding an item = %d, want %d", task.EstimatedPomodoros, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/anggara-26/pomodoro-backend.git/app/realtime"
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// tickInterval is how often a stream receives a snapshot of the running
+// session. It also detects closed connections when no session is running.
+const tickInterval = 10 * time.Second
+
+// @Summary        Create Event Stream Ticket
+// @Description    Issues a single use ticket that opens the event stream without an Authorization header, for EventSource clients. It expires after 30 seconds.
+// @Tags           Pomodoro Session
+// @Produce        json
+// @Success        201 {object} Response{data=auth.Ticket}
+// @Security       BearerAuth
+// @Router         /api/v1/events/ticket [post]
+func (h *Handler) CreateEventTicket(c *fiber.Ctx) error {
+	ticket, err := h.tickets.Issue(currentUser(c).ID, time.Now().UTC())
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(Response{
+			Message: "Failed to create ticket",
+			Code:    http.StatusInternalServerError,
+		})
+	}
+
+	return c.Status(http.StatusCreated).JSON(Response{
+		Message: "Ticket created successfully",
+		Code:    http.StatusCreated,
+		Data:    ticket,
+	})
+}
+
+// @Summary        Stream Session Events
+// @Description    Streams start, pause, resume, end and skip events of the caller's sessions as Server-Sent Events, with a tick snapshot of the running session every few seconds. EventSource clients pass a ticket from /api/v1/events/ticket instead of the Authorization header.
+// @Tags           Pomodoro Session
+// @Produce        text/event-stream
+// @Param          ticket query string false "Event stream ticket, if the token is not sent in the Authorization header"
+// @Success        200 {object} realtime.Event
+// @Security       BearerAuth
+// @Router         /api/v1/events [get]
+func (h *Handler) StreamSessionEvents(c *fiber.Ctx) error {
+	userID := currentUser(c).ID
+	events, unsubscribe := h.hub.Subscribe(userID)
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer unsubscribe()
+
+		ticker := time.NewTicker(tickInterval)
+		defer ticker.Stop()
+
+		// The request context is released once the handler returns, so the
+		// stream lives on the server context and ends with it or on a failed
+		// write.
+		if err := h.writeTick(w, userID); err != nil {
+			return
+		}
+		for {
+			select {
+			case <-h.ctx.Done():
+				drainEvents(w, events)
+				return
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				if err := writeEvent(w, event); err != nil {
+					return
+				}
+			case <-ticker.C:
+				if err := h.writeTick(w, userID); err != nil {
+					return
+				}
+			}
+		}
+	})
+
+	return nil
+}
+
+// writeTick sends a snapshot of the running session, or a comment to keep the
+// connection alive if there is none.
+func (h *Handler) writeTick(w *bufio.Writer, userID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(h.ctx, tickInterval)
+	defer cancel()
+
+	session, err := h.models.Sessions.FindCurrent(ctx, userID)
+	if err != nil {
+		if _, err := w.WriteString(": keep-alive\n\n"); err != nil {
+			return err
+		}
+		return w.Flush()
+	}
+
+	return writeEvent(w, realtime.NewEvent(realtime.EventTick, session, time.Now().UTC()))
+}
+
+// drainEvents sends the events already published to a stream that is closing.
+func drainEvents(w *bufio.Writer, events <-chan realtime.Event) {
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok || writeEvent(w, event) != nil {
+				return
+			}
+		default:
+			return
+		}
+	}
+}
+
+func writeEvent(w *bufio.Writer, event realtime.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+		return err
+	}
+
+	return w.Flush()
+}
@@ -0,0 +1,157 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anggara-26/pomodoro-backend.git/app/model"
+	"github.com/anggara-26/pomodoro-backend.git/app/realtime"
+	"github.com/anggara-26/pomodoro-backend.git/pkg/auth"
+)
+
+// eventTicket returns a ticket that opens the event stream for user.
+func (s *testServer) eventTicket(t *testing.T, user testUser) string {
+	t.Helper()
+
+	res := s.do(t, user, "POST", "/api/v1/events/ticket", nil)
+	if res.Status != http.StatusCreated {
+		t.Fatalf("create ticket: status %d: %s", res.Status, res.Message)
+	}
+	var ticket auth.Ticket
+	res.decode(t, &ticket)
+
+	return ticket.Ticket
+}
+
+// stream opens the event stream at path without an Authorization header and
+// returns the status and the events sent until the stream ends.
+func (s *testServer) stream(path string) (int, []realtime.Event, error) {
+	res, err := s.app.Test(httptest.NewRequest("GET", path, nil), -1)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var events []realtime.Event
+	for _, message := range strings.Split(string(body), "\n\n") {
+		for _, line := range strings.Split(message, "\n") {
+			if data, ok := strings.CutPrefix(line, "data: "); ok {
+				var event realtime.Event
+				if err := json.Unmarshal([]byte(data), &event); err != nil {
+					return 0, nil, err
+				}
+				events = append(events, event)
+			}
+		}
+	}
+
+	return res.StatusCode, events, nil
+}
+
+func TestStreamSessionEvents(t *testing.T) {
+	s := newTestServer(t)
+	user := s.signUp(t, "user")
+	first := s.startSession(t, user)
+	ticket := s.eventTicket(t, user)
+
+	type result struct {
+		status int
+		events []realtime.Event
+		err    error
+	}
+	done := make(chan result, 1)
+	go func() {
+		status, events, err := s.stream("/api/v1/events?ticket=" + ticket)
+		done <- result{status, events, err}
+	}()
+
+	select {
+	case <-s.hub.subscribed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("stream did not subscribe")
+	}
+
+	// Starting over ends the first session before the second starts.
+	second := s.startSession(t, user)
+
+	// Shutting the server down ends the stream once the queued events are sent.
+	s.shutdown()
+	var got result
+	select {
+	case got = <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("stream did not end on shutdown")
+	}
+	if got.err != nil {
+		t.Fatal(got.err)
+	}
+	if got.status != http.StatusOK {
+		t.Fatalf("status = %d, want %d", got.status, http.StatusOK)
+	}
+
+	// The stream opens with a tick of whichever session runs by the time it writes.
+	if len(got.events) == 0 || got.events[0].Type != realtime.EventTick {
+		t.Fatalf("events = %+v, want a tick first", got.events)
+	}
+	events := got.events[1:]
+
+	want := []struct {
+		kind   realtime.EventType
+		id     string
+		status model.SessionStatus
+	}{
+		{realtime.EventEnd, first, model.SessionBreak},
+		{realtime.EventStart, second, model.SessionActive},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("events = %+v, want %d after the tick", events, len(want))
+	}
+	for i, w := range want {
+		event := events[i]
+		if event.Type != w.kind || event.Session.ID.Hex() != w.id || event.Session.Status != w.status {
+			t.Errorf("event %d = %s of %s (%s), want %s of %s (%s)", i, event.Type, event.Session.ID.Hex(), event.Session.Status, w.kind, w.id, w.status)
+		}
+	}
+}
+
+func TestStreamSessionEventsTicket(t *testing.T) {
+	s := newTestServer(t)
+	user := s.signUp(t, "user")
+	ticket := s.eventTicket(t, user)
+	s.shutdown()
+
+	tests := []struct {
+		name string
+		path string
+		want int
+	}{
+		{"no credentials", "/api/v1/events", http.StatusUnauthorized},
+		{"unknown ticket", "/api/v1/events?ticket=unknown", http.StatusUnauthorized},
+		{"ticket", "/api/v1/events?ticket=" + ticket, http.StatusOK},
+		{"redeemed ticket", "/api/v1/events?ticket=" + ticket, http.StatusUnauthorized},
+		// Tokens are not taken from the URL, where access logs would keep them.
+		{"token in the query", "/api/v1/events?access_token=" + user.token, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, _, err := s.stream(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if status != tt.want {
+				t.Errorf("status = %d, want %d", status, tt.want)
+			}
+		})
+	}
+}
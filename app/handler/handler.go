@@ -1,8 +1,14 @@
 package handler
 
 import (
+	"context"
 	"net/http"
 
+	"github.com/anggara-26/pomodoro-backend.git/app/model"
+	"github.com/anggara-26/pomodoro-backend.git/app/realtime"
+	"github.com/anggara-26/pomodoro-backend.git/app/scheduler"
+	"github.com/anggara-26/pomodoro-backend.git/pkg/auth"
+	"github.com/anggara-26/pomodoro-backend.git/pkg/middleware"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -11,6 +17,28 @@ type Response struct {
 	Code    int         `json:"code"`
 	Data    interface{} `json:"data,omitempty"`
 	Total   int64       `json:"total,omitempty"`
+	// NextCursor is set on cursor paginated listings while more items follow.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// Handler serves the API endpoints on top of the injected repositories.
+type Handler struct {
+	// ctx is done once the server shuts down, which ends open event streams.
+	ctx       context.Context
+	models    model.Models
+	scheduler *scheduler.Scheduler
+	hub       realtime.Hub
+	tickets   *auth.Tickets
+}
+
+func New(ctx context.Context, models model.Models, scheduler *scheduler.Scheduler, hub realtime.Hub, tickets *auth.Tickets) *Handler {
+	return &Handler{
+		ctx:       ctx,
+		models:    models,
+		scheduler: scheduler,
+		hub:       hub,
+		tickets:   tickets,
+	}
 }
 
 // HealthCheck checks the health of the server
@@ -30,3 +58,11 @@ func HealthCheck(c *fiber.Ctx) error {
 
 	return c.Status(http.StatusOK).JSON(res)
 }
+
+// maxPageSize bounds the limit of paginated listings.
+const maxPageSize = 100
+
+// currentUser returns the caller resolved by middleware.AuthMiddleware.
+func currentUser(c *fiber.Ctx) model.User {
+	return c.Locals(middleware.UserKey).(model.User)
+}
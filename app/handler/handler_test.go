@@ -0,0 +1,254 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anggara-26/pomodoro-backend.git/app/handler"
+	"github.com/anggara-26/pomodoro-backend.git/app/model"
+	"github.com/anggara-26/pomodoro-backend.git/app/realtime"
+	"github.com/anggara-26/pomodoro-backend.git/app/repository/memory"
+	"github.com/anggara-26/pomodoro-backend.git/app/scheduler"
+	"github.com/anggara-26/pomodoro-backend.git/pkg/auth"
+	"github.com/anggara-26/pomodoro-backend.git/pkg/router"
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const testProjectID = "pomodoro-test"
+
+var (
+	signingKey     *rsa.PrivateKey
+	signingKeyOnce sync.Once
+)
+
+// testServer runs the real router and middleware on in-memory repositories,
+// authenticating callers with tokens signed by a key from a local JWKS file.
+type testServer struct {
+	app    *fiber.App
+	models model.Models
+	hub    *testHub
+	// shutdown ends the server context, as stopping the API does.
+	shutdown context.CancelFunc
+}
+
+// testHub is a realtime.LocalHub that signals every subscription, so tests
+// can publish once a stream listens.
+type testHub struct {
+	*realtime.LocalHub
+	subscribed chan struct{}
+}
+
+func (h *testHub) Subscribe(userID primitive.ObjectID) (<-chan realtime.Event, func()) {
+	events, unsubscribe := h.LocalHub.Subscribe(userID)
+	select {
+	case h.subscribed <- struct{}{}:
+	default:
+	}
+
+	return events, unsubscribe
+}
+
+// testUser is a signed up caller of a testServer.
+type testUser struct {
+	ID    string
+	token string
+}
+
+// testResponse is a decoded handler.Response.
+type testResponse struct {
+	Status     int
+	Message    string          `json:"message"`
+	Data       json.RawMessage `json:"data"`
+	Total      int64           `json:"total"`
+	NextCursor string          `json:"next_cursor"`
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+
+	signingKeyOnce.Do(func() {
+		var err error
+		if signingKey, err = rsa.GenerateKey(rand.Reader, 2048); err != nil {
+			panic(err)
+		}
+	})
+
+	set := map[string]any{"keys": []map[string]string{{
+		"kid": "test-key",
+		"kty": "RSA",
+		"n":   base64.RawURLEncoding.EncodeToString(signingKey.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(signingKey.E)).Bytes()),
+	}}}
+	data, err := json.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	models := memory.NewModels()
+	hub := &testHub{LocalHub: realtime.NewLocalHub(), subscribed: make(chan struct{}, 8)}
+	app := fiber.New()
+	tickets := auth.NewTickets(30 * time.Second)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	router.CreateRouter(app, handler.New(ctx, models, scheduler.New(models, hub), hub, tickets), auth.NewVerifier(testProjectID, auth.NewFileKeySource(path)), tickets, models.Users)
+
+	return &testServer{app: app, models: models, hub: hub, shutdown: cancel}
+}
+
+// token returns a Firebase ID token for uid, signed by the test key.
+func token(t *testing.T, uid string) string {
+	t.Helper()
+
+	now := time.Now()
+	encode := func(v any) string {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+
+	signed := encode(map[string]any{"alg": "RS256", "kid": "test-key"}) + "." + encode(map[string]any{
+		"iss":       "https://securetoken.google.com/" + testProjectID,
+		"aud":       testProjectID,
+		"sub":       uid,
+		"iat":       now.Unix(),
+		"exp":       now.Add(time.Hour).Unix(),
+		"auth_time": now.Unix(),
+		"email":     uid + "@example.com",
+	})
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, signingKey, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// signUp registers a user for the Firebase UID uid.
+func (s *testServer) signUp(t *testing.T, uid string) testUser {
+	t.Helper()
+
+	user := testUser{token: token(t, uid)}
+	res := s.do(t, user, "POST", "/api/v1/users", map[string]string{"email": uid + "@example.com", "name": uid})
+	if res.Status != fiber.StatusCreated {
+		t.Fatalf("sign up %s: status %d: %s", uid, res.Status, res.Message)
+	}
+	user.ID = res.insertedID(t)
+
+	return user
+}
+
+// do sends body as JSON to path on behalf of user.
+func (s *testServer) do(t *testing.T, user testUser, method, path string, body any) testResponse {
+	t.Helper()
+
+	res, err := s.send(user, method, path, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return res
+}
+
+// doWithHeader is do with additional request headers.
+func (s *testServer) doWithHeader(t *testing.T, user testUser, method, path string, body any, header map[string]string) testResponse {
+	t.Helper()
+
+	res, err := s.sendWithHeader(user, method, path, body, header)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return res
+}
+
+// send is do for callers off the test goroutine, which must not call t.Fatal.
+func (s *testServer) send(user testUser, method, path string, body any) (testResponse, error) {
+	return s.sendWithHeader(user, method, path, body, nil)
+}
+
+func (s *testServer) sendWithHeader(user testUser, method, path string, body any, header map[string]string) (testResponse, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return testResponse{}, err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+user.token)
+	for key, value := range header {
+		req.Header.Set(key, value)
+	}
+
+	res, err := s.app.Test(req, -1)
+	if err != nil {
+		return testResponse{}, fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	defer res.Body.Close()
+
+	decoded := testResponse{Status: res.StatusCode}
+	if err := json.NewDecoder(res.Body).Decode(&decoded); err != nil {
+		return testResponse{}, fmt.Errorf("%s %s: decode response: %w", method, path, err)
+	}
+
+	return decoded, nil
+}
+
+// createTask creates a task owned by user and returns its ID.
+func (s *testServer) createTask(t *testing.T, user testUser, title string) string {
+	t.Helper()
+
+	res := s.do(t, user, "POST", "/api/v1/tasks", map[string]any{"title": title, "estimated_pomodoros": 2})
+	if res.Status != fiber.StatusCreated {
+		t.Fatalf("create task: status %d: %s", res.Status, res.Message)
+	}
+
+	return res.insertedID(t)
+}
+
+func (r testResponse) insertedID(t *testing.T) string {
+	t.Helper()
+
+	var data struct {
+		InsertedID string `json:"InsertedID"`
+	}
+	if err := json.Unmarshal(r.Data, &data); err != nil || data.InsertedID == "" {
+		t.Fatalf("response has no InsertedID: %s", r.Data)
+	}
+
+	return data.InsertedID
+}
+
+func (r testResponse) decode(t *testing.T, v any) {
+	t.Helper()
+
+	if err := json.Unmarshal(r.Data, v); err != nil {
+		t.Fatalf("decode %s: %v", r.Data, err)
+	}
+}
@@ -0,0 +1,102 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/anggara-26/pomodoro-backend.git/app/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// manualOrder returns the titles of the tasks of user in manual order.
+func (s *testServer) manualOrder(t *testing.T, user testUser) []string {
+	t.Helper()
+
+	var tasks []model.Task
+	s.do(t, user, "GET", "/api/v1/tasks/user/"+user.ID+"?sort=manual&limit=100", nil).decode(t, &tasks)
+	titles := make([]string, len(tasks))
+	for i, task := range tasks {
+		titles[i] = task.Title
+	}
+
+	return titles
+}
+
+func TestMoveTask(t *testing.T) {
+	tests := []struct {
+		name      string
+		positions []int64
+		move      string
+		after     string
+		before    string
+		want      []string
+	}{
+		{"to the front", nil, "c", "", "a", []string{"c", "a", "b"}},
+		{"to the back", nil, "a", "c", "", []string{"b", "c", "a"}},
+		{"after", nil, "c", "a", "", []string{"a", "c", "b"}},
+		{"before", nil, "a", "", "c", []string{"b", "a", "c"}},
+		{"between", nil, "c", "a", "b", []string{"a", "c", "b"}},
+		{"into a full gap", []int64{1, 2, 3}, "c", "a", "", []string{"a", "c", "b"}},
+		// Ties keep the listing order, newest first.
+		{"after a tie", []int64{5, 5, 5}, "a", "c", "", []string{"c", "a", "b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t)
+			user := s.signUp(t, "user")
+			userID, err := primitive.ObjectIDFromHex(user.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ids := map[string]string{}
+			for i, title := range []string{"a", "b", "c"} {
+				if tt.positions == nil {
+					ids[title] = s.createTask(t, user, title)
+					continue
+				}
+				// Positions without room between them, as left by earlier moves.
+				task := model.Task{UserID: userID, Title: title, Status: model.TaskPending, EstimatedPomodoros: 1, CreatedAt: time.Now().Add(time.Duration(i) * time.Second), Position: tt.positions[i]}
+				if err := s.models.Tasks.Create(context.Background(), &task); err != nil {
+					t.Fatal(err)
+				}
+				ids[title] = task.ID.Hex()
+			}
+
+			body := map[string]string{}
+			if tt.after != "" {
+				body["after_id"] = ids[tt.after]
+			}
+			if tt.before != "" {
+				body["before_id"] = ids[tt.before]
+			}
+			res := s.do(t, user, "PATCH", "/api/v1/tasks/"+ids[tt.move]+"/move", body)
+			if res.Status != http.StatusOK {
+				t.Fatalf("status = %d (%s), want %d", res.Status, res.Message, http.StatusOK)
+			}
+
+			if got := s.manualOrder(t, user); !slices.Equal(got, tt.want) {
+				t.Errorf("order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoveTrashedTask(t *testing.T) {
+	s := newTestServer(t)
+	user := s.signUp(t, "user")
+	a := s.createTask(t, user, "a")
+	b := s.createTask(t, user, "b")
+	s.do(t, user, "DELETE", "/api/v1/tasks/"+a, nil)
+
+	if res := s.do(t, user, "PATCH", "/api/v1/tasks/"+a+"/move", map[string]string{"after_id": b}); res.Status != http.StatusNotFound {
+		t.Errorf("moving a trashed task: status = %d (%s), want %d", res.Status, res.Message, http.StatusNotFound)
+	}
+	if res := s.do(t, user, "PATCH", "/api/v1/tasks/"+b+"/move", map[string]string{"after_id": a}); res.Status != http.StatusNotFound {
+		t.Errorf("moving after a trashed task: status = %d (%s), want %d", res.Status, res.Message, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,127 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/anggara-26/pomodoro-backend.git/app/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestTaskPagination(t *testing.T) {
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{"", http.StatusOK},
+		{"page=2&limit=100", http.StatusOK},
+		{"page=0", http.StatusBadRequest},
+		{"page=-1", http.StatusBadRequest},
+		{"limit=0", http.StatusBadRequest},
+		{"limit=-5", http.StatusBadRequest},
+		{"limit=101", http.StatusBadRequest},
+		{"cursor=&limit=0", http.StatusBadRequest},
+		{"cursor=&limit=101", http.StatusBadRequest},
+		{"cursor=&page=0", http.StatusOK},
+		{"cursor=garbage", http.StatusBadRequest},
+	}
+
+	s := newTestServer(t)
+	user := s.signUp(t, "user")
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			res := s.do(t, user, "GET", "/api/v1/tasks/user/"+user.ID+"?"+tt.query, nil)
+			if res.Status != tt.want {
+				t.Errorf("status = %d (%s), want %d", res.Status, res.Message, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskCursorPagination(t *testing.T) {
+	s := newTestServer(t)
+	user := s.signUp(t, "user")
+	userID, err := primitive.ObjectIDFromHex(user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Tasks created in the same instant only differ in _id, which breaks the tie.
+	createdAt := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	want := map[string]bool{}
+	for i := 0; i < 5; i++ {
+		at := createdAt
+		if i == 4 {
+			at = createdAt.Add(-time.Minute)
+		}
+		task := model.Task{UserID: userID, Title: "Task", Status: model.TaskPending, EstimatedPomodoros: 1, CreatedAt: at}
+		if err := s.models.Tasks.Create(context.Background(), &task); err != nil {
+			t.Fatal(err)
+		}
+		want[task.ID.Hex()] = true
+	}
+
+	var listed []model.Task
+	cursor := ""
+	for pages := 0; ; pages++ {
+		if pages > len(want) {
+			t.Fatalf("more pages than tasks, listed %d", len(listed))
+		}
+		res := s.do(t, user, "GET", "/api/v1/tasks/user/"+user.ID+"?limit=2&cursor="+cursor, nil)
+		if res.Status != http.StatusOK {
+			t.Fatalf("status = %d (%s), want %d", res.Status, res.Message, http.StatusOK)
+		}
+		var page []model.Task
+		res.decode(t, &page)
+		listed = append(listed, page...)
+		if res.NextCursor == "" {
+			break
+		}
+		cursor = res.NextCursor
+	}
+
+	if len(listed) != len(want) {
+		t.Fatalf("listed %d tasks, want %d", len(listed), len(want))
+	}
+	for i, task := range listed {
+		if !want[task.ID.Hex()] {
+			t.Errorf("task %s listed twice or unknown", task.ID.Hex())
+		}
+		delete(want, task.ID.Hex())
+
+		if i == 0 {
+			continue
+		}
+		prev := listed[i-1]
+		if prev.CreatedAt.Before(task.CreatedAt) || prev.CreatedAt.Equal(task.CreatedAt) && prev.ID.Hex() < task.ID.Hex() {
+			t.Errorf("task %d (%s, %s) listed after (%s, %s), want created_at and _id descending", i, task.CreatedAt, task.ID.Hex(), prev.CreatedAt, prev.ID.Hex())
+		}
+	}
+}
+
+func TestTrashPagination(t *testing.T) {
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{"", http.StatusOK},
+		{"page=3&limit=100", http.StatusOK},
+		{"page=0", http.StatusBadRequest},
+		{"limit=0", http.StatusBadRequest},
+		{"limit=-1", http.StatusBadRequest},
+		{"limit=101", http.StatusBadRequest},
+	}
+
+	s := newTestServer(t)
+	user := s.signUp(t, "user")
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			res := s.do(t, user, "GET", "/api/v1/tasks/trash?"+tt.query, nil)
+			if res.Status != tt.want {
+				t.Errorf("status = %d (%s), want %d", res.Status, res.Message, tt.want)
+			}
+		})
+	}
+}
@@ -1,26 +1,29 @@
 package handler
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/anggara-26/pomodoro-backend.git/app/model"
-	"github.com/anggara-26/pomodoro-backend.git/db"
+	"github.com/anggara-26/pomodoro-backend.git/app/realtime"
 	"github.com/go-playground/validator"
 	"github.com/gofiber/fiber/v2"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // @Summary        Start Pomodoro Session
-// @Description    Starts a new pomodoro session
+// @Description    Starts a new pomodoro session, the duration defaults to the settings of the caller
 // @Tags           Pomodoro Session
 // @Accept         json
 // @Produce        json
 // @Param          session body model.CreateSessionDTO true "Pomodoro Session Data"
 // @Success        201 {object} Response
+// @Security       BearerAuth
 // @Router         /api/v1/sessions/start [post]
-func StartPomodoroSession(c *fiber.Ctx) error {
+func (h *Handler) StartPomodoroSession(c *fiber.Ctx) error {
 	b := new(model.CreateSessionDTO)
 	if err := c.BodyParser(b); err != nil {
 		return c.Status(http.StatusBadRequest).JSON(Response{
@@ -29,6 +32,12 @@ func StartPomodoroSession(c *fiber.Ctx) error {
 		})
 	}
 
+	user := currentUser(c)
+	b.UserID = user.ID
+	if b.Duration == 0 {
+		b.Duration = user.Settings.CycleRules().Duration(b.Type)
+	}
+
 	validate := validator.New()
 	if err := validate.Struct(b); err != nil {
 		return c.Status(http.StatusBadRequest).JSON(Response{
@@ -37,44 +46,83 @@ func StartPomodoroSession(c *fiber.Ctx) error {
 		})
 	}
 
-	collTasks := db.GetDBCollection("tasks")
-	countTasks, err := collTasks.CountDocuments(c.Context(), bson.M{"_id": b.TaskID})
-	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(Response{
-			Message: "Failed to check task",
-			Code:    http.StatusInternalServerError,
-		})
-	}
-	if countTasks == 0 {
+	session, err := h.startSession(c.Context(), b)
+	if errors.Is(err, errTaskNotFound) {
 		return c.Status(http.StatusBadRequest).JSON(Response{
 			Message: "Task not found",
 			Code:    http.StatusBadRequest,
 		})
 	}
-
-	coll := db.GetDBCollection("sessions")
-
-	count, err := coll.CountDocuments(c.Context(), bson.M{"user_id": b.UserID, "status": model.SessionActive})
+	if errors.Is(err, model.ErrConflict) {
+		return c.Status(http.StatusConflict).JSON(Response{
+			Message: "Another session was started at the same time",
+			Code:    http.StatusConflict,
+		})
+	}
 	if err != nil {
 		return c.Status(http.StatusInternalServerError).JSON(Response{
-			Message: "Failed to check active session",
+			Message: "Failed to create session",
 			Code:    http.StatusInternalServerError,
 		})
 	}
-	if count > 0 {
-		_, err := coll.UpdateMany(c.Context(), bson.M{"user_id": b.UserID, "status": model.SessionActive}, bson.M{"$set": bson.M{"status": model.SessionBreak}})
-		if err != nil {
-			return c.Status(http.StatusInternalServerError).JSON(Response{
-				Message: "Failed to update active session status",
-				Code:    http.StatusInternalServerError,
+
+	return c.Status(http.StatusCreated).JSON(Response{
+		Message: "Session created successfully",
+		Code:    http.StatusCreated,
+		Data:    session.ID,
+	})
+}
+
+// @Summary        Start Next Pomodoro Session
+// @Description    Starts the session that follows the history of the caller: a break after focus, with a long break after every few focus sessions, and focus after a break or an idle gap
+// @Tags           Pomodoro Session
+// @Accept         json
+// @Produce        json
+// @Param          session body model.NextSessionDTO false "Pomodoro Session Data"
+// @Success        201 {object} Response{data=model.SessionState}
+// @Security       BearerAuth
+// @Router         /api/v1/sessions/next [post]
+func (h *Handler) StartNextPomodoroSession(c *fiber.Ctx) error {
+	b := new(model.NextSessionDTO)
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(b); err != nil {
+			return c.Status(http.StatusBadRequest).JSON(Response{
+				Message: err.Error(),
+				Code:    http.StatusBadRequest,
 			})
 		}
 	}
 
-	b.StartedAt = time.Now().UTC()
-	b.Status = model.SessionActive
+	user := currentUser(c)
+	rules := user.Settings.CycleRules()
 
-	result, err := coll.InsertOne(c.Context(), b)
+	history, err := h.models.Sessions.ListEnded(c.Context(), user.ID, int64(rules.LongBreakInterval*4))
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(Response{
+			Message: "Failed to get session history",
+			Code:    http.StatusInternalServerError,
+		})
+	}
+
+	next := rules.Next(history, time.Now().UTC())
+	session, err := h.startSession(c.Context(), &model.CreateSessionDTO{
+		UserID:   user.ID,
+		TaskID:   b.TaskID,
+		Duration: rules.Duration(next),
+		Type:     next,
+	})
+	if errors.Is(err, errTaskNotFound) {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: "Task not found",
+			Code:    http.StatusBadRequest,
+		})
+	}
+	if errors.Is(err, model.ErrConflict) {
+		return c.Status(http.StatusConflict).JSON(Response{
+			Message: "Another session was started at the same time",
+			Code:    http.StatusConflict,
+		})
+	}
 	if err != nil {
 		return c.Status(http.StatusInternalServerError).JSON(Response{
 			Message: "Failed to create session",
@@ -85,20 +133,58 @@ func StartPomodoroSession(c *fiber.Ctx) error {
 	return c.Status(http.StatusCreated).JSON(Response{
 		Message: "Session created successfully",
 		Code:    http.StatusCreated,
-		Data:    result.InsertedID,
+		Data:    model.NewSessionState(session, session.StartedAt),
 	})
 }
 
+var errTaskNotFound = errors.New("task not found")
+
+// startSession moves the running session of the user to a break and starts b in its place.
+func (h *Handler) startSession(ctx context.Context, b *model.CreateSessionDTO) (model.Session, error) {
+	if b.TaskID != nil {
+		_, err := h.models.Tasks.FindByID(ctx, b.UserID, *b.TaskID)
+		if errors.Is(err, model.ErrNotFound) {
+			return model.Session{}, errTaskNotFound
+		}
+		if err != nil {
+			return model.Session{}, err
+		}
+	}
+
+	session := model.Session{
+		UserID:    b.UserID,
+		TaskID:    b.TaskID,
+		StartedAt: time.Now().UTC(),
+		Duration:  b.Duration,
+		Type:      b.Type,
+		Status:    model.SessionActive,
+	}
+
+	demoted, err := h.models.Sessions.Start(ctx, &session)
+	if err != nil {
+		return model.Session{}, err
+	}
+	for _, previous := range demoted {
+		h.scheduler.Cancel(previous.ID)
+		h.hub.Publish(previous.UserID, realtime.NewEvent(realtime.EndEventType(previous.Status), previous, session.StartedAt))
+	}
+	h.scheduler.Schedule(session)
+	h.hub.Publish(session.UserID, realtime.NewEvent(realtime.EventStart, session, session.StartedAt))
+
+	return session, nil
+}
+
 // @Summary        End Pomodoro Session
-// @Description    Ends an active pomodoro session
+// @Description    Ends an active or paused pomodoro session, a completed focus session counts a pomodoro on its task
 // @Tags           Pomodoro Session
 // @Accept         json
 // @Produce        json
 // @Param          id path string true "Session ID"
 // @param          is_skip query bool false "Skip the session"
 // @Success        200 {object} Response
+// @Security       BearerAuth
 // @Router         /api/v1/sessions/end/{id} [post]
-func EndPomodoroSession(c *fiber.Ctx) error {
+func (h *Handler) EndPomodoroSession(c *fiber.Ctx) error {
 	id := c.Params("id")
 	if id == "" {
 		return c.Status(http.StatusBadRequest).JSON(Response{
@@ -115,41 +201,275 @@ func EndPomodoroSession(c *fiber.Ctx) error {
 		})
 	}
 
-	coll := db.GetDBCollection("sessions")
+	user := currentUser(c)
+	b := model.EndSession{
+		EndedAt:          time.Now().UTC(),
+		Status:           model.SessionCompleted,
+		AutoCompleteTask: user.Settings.AutoCompleteTasks,
+	}
+
+	if isSkipped := c.Query("is_skip"); isSkipped == "true" {
+		b.Status = model.SessionSkipped
+	}
 
-	count, err := coll.CountDocuments(c.Context(), bson.M{"_id": objectID, "status": model.SessionActive})
+	session, err := h.models.Sessions.End(c.Context(), user.ID, objectID, b)
+	if errors.Is(err, model.ErrNotFound) {
+		return c.Status(http.StatusNotFound).JSON(Response{
+			Message: "Session not found or already ended",
+			Code:    http.StatusNotFound,
+		})
+	}
 	if err != nil {
 		return c.Status(http.StatusInternalServerError).JSON(Response{
-			Message: "Failed to check active session",
+			Message: "Failed to end session",
 			Code:    http.StatusInternalServerError,
 		})
 	}
-	if count == 0 {
+	h.scheduler.Cancel(objectID)
+	h.hub.Publish(user.ID, realtime.NewEvent(realtime.EndEventType(b.Status), session, b.EndedAt))
+
+	return c.Status(http.StatusOK).JSON(Response{
+		Message: "Session ended successfully",
+		Code:    http.StatusOK,
+	})
+}
+
+// @Summary        Pause Pomodoro Session
+// @Description    Pauses the timer of an active pomodoro session
+// @Tags           Pomodoro Session
+// @Produce        json
+// @Param          id path string true "Session ID"
+// @Success        200 {object} Response{data=model.SessionState}
+// @Security       BearerAuth
+// @Router         /api/v1/sessions/{id}/pause [post]
+func (h *Handler) PausePomodoroSession(c *fiber.Ctx) error {
+	objectID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
 		return c.Status(http.StatusBadRequest).JSON(Response{
-			Message: "Session not found or already ended",
+			Message: "Invalid session ID",
 			Code:    http.StatusBadRequest,
 		})
 	}
 
-	b := model.EndSession{
-		EndedAt: time.Now().UTC(),
-		Status:  model.SessionCompleted,
+	now := time.Now().UTC()
+	session, err := h.models.Sessions.Pause(c.Context(), currentUser(c).ID, objectID, now)
+	if errors.Is(err, model.ErrNotFound) {
+		return c.Status(http.StatusNotFound).JSON(Response{
+			Message: "Session not found or not active",
+			Code:    http.StatusNotFound,
+		})
+	}
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(Response{
+			Message: "Failed to pause session",
+			Code:    http.StatusInternalServerError,
+		})
 	}
+	h.scheduler.Cancel(session.ID)
+	h.hub.Publish(session.UserID, realtime.NewEvent(realtime.EventPause, session, now))
 
-	if isSkipped := c.Query("is_skip"); isSkipped == "true" {
-		b.Status = model.SessionSkipped
+	return c.Status(http.StatusOK).JSON(Response{
+		Message: "Session paused successfully",
+		Code:    http.StatusOK,
+		Data:    model.NewSessionState(session, now),
+	})
+}
+
+// @Summary        Resume Pomodoro Session
+// @Description    Resumes the timer of a paused pomodoro session
+// @Tags           Pomodoro Session
+// @Produce        json
+// @Param          id path string true "Session ID"
+// @Success        200 {object} Response{data=model.SessionState}
+// @Security       BearerAuth
+// @Router         /api/v1/sessions/{id}/resume [post]
+func (h *Handler) ResumePomodoroSession(c *fiber.Ctx) error {
+	objectID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: "Invalid session ID",
+			Code:    http.StatusBadRequest,
+		})
 	}
 
-	_, err = coll.UpdateOne(c.Context(), bson.M{"_id": objectID}, bson.M{"$set": b})
+	now := time.Now().UTC()
+	session, err := h.models.Sessions.Resume(c.Context(), currentUser(c).ID, objectID, now)
+	if errors.Is(err, model.ErrNotFound) {
+		return c.Status(http.StatusNotFound).JSON(Response{
+			Message: "Session not found or not paused",
+			Code:    http.StatusNotFound,
+		})
+	}
+	if errors.Is(err, model.ErrConflict) {
+		return c.Status(http.StatusConflict).JSON(Response{
+			Message: "Another session is active",
+			Code:    http.StatusConflict,
+		})
+	}
 	if err != nil {
 		return c.Status(http.StatusInternalServerError).JSON(Response{
-			Message: "Failed to end session",
+			Message: "Failed to resume session",
 			Code:    http.StatusInternalServerError,
 		})
 	}
+	h.scheduler.Schedule(session)
+	h.hub.Publish(session.UserID, realtime.NewEvent(realtime.EventResume, session, now))
 
 	return c.Status(http.StatusOK).JSON(Response{
-		Message: "Session ended successfully",
+		Message: "Session resumed successfully",
+		Code:    http.StatusOK,
+		Data:    model.NewSessionState(session, now),
+	})
+}
+
+// @Summary        Get Current Pomodoro Session
+// @Description    Retrieves the active or paused session of the caller with its remaining time and linked task
+// @Tags           Pomodoro Session
+// @Produce        json
+// @Success        200 {object} Response{data=model.CurrentSession}
+// @Security       BearerAuth
+// @Router         /api/v1/sessions/current [get]
+func (h *Handler) GetCurrentPomodoroSession(c *fiber.Ctx) error {
+	userID := currentUser(c).ID
+
+	session, err := h.models.Sessions.FindCurrent(c.Context(), userID)
+	if errors.Is(err, model.ErrNotFound) {
+		return c.Status(http.StatusNotFound).JSON(Response{
+			Message: "No running session",
+			Code:    http.StatusNotFound,
+		})
+	}
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(Response{
+			Message: "Failed to get session",
+			Code:    http.StatusInternalServerError,
+		})
+	}
+
+	current := model.CurrentSession{SessionState: model.NewSessionState(session, time.Now().UTC())}
+	if session.TaskID != nil {
+		task, err := h.models.Tasks.FindByID(c.Context(), userID, *session.TaskID)
+		if err != nil && !errors.Is(err, model.ErrNotFound) {
+			return c.Status(http.StatusInternalServerError).JSON(Response{
+				Message: "Failed to get task",
+				Code:    http.StatusInternalServerError,
+			})
+		}
+		if err == nil {
+			current.Task = &task
+		}
+	}
+
+	return c.Status(http.StatusOK).JSON(Response{
+		Message: "Session found",
+		Code:    http.StatusOK,
+		Data:    current,
+	})
+}
+
+// sessionSorts are the fields sessions can be sorted by.
+var sessionSorts = map[string]bool{"started_at": true, "ended_at": true, "duration": true}
+
+// @Summary        Get Sessions by User ID
+// @Description    Retrieves the session history of a user with optional filters, sorting and pagination
+// @Tags           Pomodoro Session
+// @Produce        json
+// @Param          id path string true "User ID"
+// @Param          type query string false "Session Type"
+// @Param          status query string false "Session Status"
+// @Param          task_id query string false "Task ID"
+// @Param          start_date query string false "Started at or after"
+// @Param          end_date query string false "Started at or before"
+// @Param          sort query string false "started_at, ended_at or duration, prefixed with - for descending order" default(-started_at)
+// @Param          page query int false "Page number"
+// @Param          limit query int false "Number of sessions per page, at most 100" default(10)
+// @Success        200 {object} Response{data=[]model.Session}
+// @Security       BearerAuth
+// @Router         /api/v1/sessions/user/{id} [get]
+func (h *Handler) GetSessionsByUserID(c *fiber.Ctx) error {
+	objectID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: "Invalid ID",
+			Code:    http.StatusBadRequest,
+		})
+	}
+	if objectID != currentUser(c).ID {
+		return c.Status(http.StatusNotFound).JSON(Response{
+			Message: "User not found",
+			Code:    http.StatusNotFound,
+		})
+	}
+
+	filter := model.SessionFilter{
+		UserID: objectID,
+		Type:   model.SessionType(c.Query("type")),
+		Status: model.SessionStatus(c.Query("status")),
+	}
+
+	if taskID := c.Query("task_id"); taskID != "" {
+		taskObjectID, err := primitive.ObjectIDFromHex(taskID)
+		if err != nil {
+			return c.Status(http.StatusBadRequest).JSON(Response{
+				Message: "Invalid task ID",
+				Code:    http.StatusBadRequest,
+			})
+		}
+		filter.TaskID = &taskObjectID
+	}
+	if startDate := c.Query("start_date"); startDate != "" {
+		start, err := time.Parse(time.RFC3339, startDate)
+		if err != nil {
+			return c.Status(http.StatusBadRequest).JSON(Response{
+				Message: "Invalid start date format",
+				Code:    http.StatusBadRequest,
+			})
+		}
+		filter.StartDate = &start
+	}
+	if endDate := c.Query("end_date"); endDate != "" {
+		end, err := time.Parse(time.RFC3339, endDate)
+		if err != nil {
+			return c.Status(http.StatusBadRequest).JSON(Response{
+				Message: "Invalid end date format",
+				Code:    http.StatusBadRequest,
+			})
+		}
+		filter.EndDate = &end
+	}
+
+	sortBy := c.Query("sort", "-started_at")
+	filter.SortBy, filter.SortDesc = strings.TrimPrefix(sortBy, "-"), strings.HasPrefix(sortBy, "-")
+	if !sessionSorts[filter.SortBy] {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: "Invalid sort field",
+			Code:    http.StatusBadRequest,
+		})
+	}
+
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 10)
+	if page < 1 || limit < 1 || limit > maxPageSize {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: "Invalid pagination",
+			Code:    http.StatusBadRequest,
+		})
+	}
+	filter.Skip = int64((page - 1) * limit)
+	filter.Limit = int64(limit)
+
+	sessions, total, err := h.models.Sessions.List(c.Context(), filter)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(Response{
+			Message: "Failed to get sessions",
+			Code:    http.StatusInternalServerError,
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(Response{
+		Message: "Sessions found",
 		Code:    http.StatusOK,
+		Data:    sessions,
+		Total:   total,
 	})
 }
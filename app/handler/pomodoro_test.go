@@ -0,0 +1,259 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anggara-26/pomodoro-backend.git/app/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// startSession starts a focus session for user and returns its ID.
+func (s *testServer) startSession(t *testing.T, user testUser) string {
+	t.Helper()
+
+	res := s.do(t, user, "POST", "/api/v1/sessions/start", map[string]any{"type": model.Focus, "duration": 25})
+	if res.Status != http.StatusCreated {
+		t.Fatalf("start session: status %d: %s", res.Status, res.Message)
+	}
+	var id string
+	res.decode(t, &id)
+
+	return id
+}
+
+func TestSessionOwnership(t *testing.T) {
+	s := newTestServer(t)
+	owner := s.signUp(t, "owner")
+	other := s.signUp(t, "other")
+	sessionID := s.startSession(t, owner)
+	taskID := s.createTask(t, owner, "Task")
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   any
+		want   int
+	}{
+		{"pause", "POST", "/api/v1/sessions/" + sessionID + "/pause", nil, http.StatusNotFound},
+		{"resume", "POST", "/api/v1/sessions/" + sessionID + "/resume", nil, http.StatusNotFound},
+		{"end", "POST", "/api/v1/sessions/end/" + sessionID, nil, http.StatusNotFound},
+		{"list", "GET", "/api/v1/sessions/user/" + owner.ID, nil, http.StatusNotFound},
+		{"current", "GET", "/api/v1/sessions/current", nil, http.StatusNotFound},
+		{"start on task", "POST", "/api/v1/sessions/start", map[string]any{"type": model.Focus, "duration": 25, "task_id": taskID}, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := s.do(t, other, tt.method, tt.path, tt.body)
+			if res.Status != tt.want {
+				t.Errorf("status = %d (%s), want %d", res.Status, res.Message, tt.want)
+			}
+		})
+	}
+
+	var current model.CurrentSession
+	s.do(t, owner, "GET", "/api/v1/sessions/current", nil).decode(t, &current)
+	if current.ID.Hex() != sessionID || current.Status != model.SessionActive {
+		t.Errorf("current session = %+v, want %s still active", current.SessionState, sessionID)
+	}
+
+	if res := s.do(t, owner, "POST", "/api/v1/sessions/end/"+sessionID, nil); res.Status != http.StatusOK {
+		t.Errorf("end by owner: status = %d (%s), want %d", res.Status, res.Message, http.StatusOK)
+	}
+}
+
+func TestSessionStatusTransitions(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps []string
+		want  []int
+	}{
+		{"pause and resume", []string{"pause", "resume"}, []int{http.StatusOK, http.StatusOK}},
+		{"pause twice", []string{"pause", "pause"}, []int{http.StatusOK, http.StatusNotFound}},
+		{"resume active", []string{"resume"}, []int{http.StatusNotFound}},
+		{"end paused", []string{"pause", "end"}, []int{http.StatusOK, http.StatusOK}},
+		{"end twice", []string{"end", "end"}, []int{http.StatusOK, http.StatusNotFound}},
+		{"pause ended", []string{"end", "pause"}, []int{http.StatusOK, http.StatusNotFound}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t)
+			user := s.signUp(t, "user")
+			sessionID := s.startSession(t, user)
+
+			paths := map[string]string{
+				"pause":  "/api/v1/sessions/" + sessionID + "/pause",
+				"resume": "/api/v1/sessions/" + sessionID + "/resume",
+				"end":    "/api/v1/sessions/end/" + sessionID,
+			}
+			for i, step := range tt.steps {
+				if res := s.do(t, user, "POST", paths[step], nil); res.Status != tt.want[i] {
+					t.Fatalf("%s: status = %d (%s), want %d", step, res.Status, res.Message, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestEndSessionCountsPomodoro(t *testing.T) {
+	s := newTestServer(t)
+	user := s.signUp(t, "user")
+	taskID := s.createTask(t, user, "Task")
+
+	var sessionID string
+	s.do(t, user, "POST", "/api/v1/sessions/start", map[string]any{"type": model.Focus, "task_id": taskID}).decode(t, &sessionID)
+	if res := s.do(t, user, "POST", "/api/v1/sessions/end/"+sessionID, nil); res.Status != http.StatusOK {
+		t.Fatalf("end: status %d: %s", res.Status, res.Message)
+	}
+
+	var task model.Task
+	s.do(t, user, "GET", "/api/v1/tasks/"+taskID, nil).decode(t, &task)
+	if task.CompletedPomodoros != 1 || task.Status != model.TaskInProgress {
+		t.Errorf("task = %+v, want one pomodoro and in progress", task)
+	}
+}
+
+func TestConcurrentSessionStarts(t *testing.T) {
+	s := newTestServer(t)
+	user := s.signUp(t, "user")
+
+	const requests = 20
+	statuses := make(chan int, requests)
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			path, body := "/api/v1/sessions/start", any(map[string]any{"type": model.Focus})
+			if i%2 == 1 {
+				path, body = "/api/v1/sessions/next", nil
+			}
+			res, err := s.send(user, "POST", path, body)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			statuses <- res.Status
+		}(i)
+	}
+	wg.Wait()
+	close(statuses)
+
+	for status := range statuses {
+		if status != http.StatusCreated && status != http.StatusConflict {
+			t.Errorf("status = %d, want %d or %d", status, http.StatusCreated, http.StatusConflict)
+		}
+	}
+
+	userID, err := primitive.ObjectIDFromHex(user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessions, err := s.models.Sessions.ListActive(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var active int
+	for _, session := range sessions {
+		if session.UserID == userID {
+			active++
+		}
+	}
+	if active != 1 {
+		t.Errorf("active sessions = %d, want 1", active)
+	}
+}
+
+// pastSession is an ended session of a seeded history.
+type pastSession struct {
+	kind   model.SessionType
+	status model.SessionStatus
+	// endedAgo is how many minutes before now the session ended.
+	endedAgo int
+}
+
+// seedHistory records sessions of user, given oldest first, each running for
+// the default length of its type.
+func (s *testServer) seedHistory(t *testing.T, user testUser, history []pastSession) {
+	t.Helper()
+
+	ctx := context.Background()
+	userID, _ := primitive.ObjectIDFromHex(user.ID)
+	rules := model.DefaultSettings.CycleRules()
+	now := time.Now().UTC()
+	for _, past := range history {
+		length := time.Duration(rules.Duration(past.kind)) * time.Minute
+		endedAt := now.Add(-time.Duration(past.endedAgo) * time.Minute)
+		session := model.Session{UserID: userID, StartedAt: endedAt.Add(-length), Duration: rules.Duration(past.kind), Type: past.kind, Status: model.SessionActive}
+		if _, err := s.models.Sessions.Start(ctx, &session); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.models.Sessions.End(ctx, userID, session.ID, model.EndSession{EndedAt: endedAt, Status: past.status}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestStartNextSession(t *testing.T) {
+	const (
+		done    = model.SessionCompleted
+		skipped = model.SessionSkipped
+	)
+	focus := func(status model.SessionStatus, endedAgo int) pastSession {
+		return pastSession{model.Focus, status, endedAgo}
+	}
+	short := func(status model.SessionStatus, endedAgo int) pastSession {
+		return pastSession{model.ShortBreak, status, endedAgo}
+	}
+
+	// fourFocus is a full cycle of the default interval of four, each session
+	// starting as the previous one ends.
+	fourFocus := []pastSession{focus(done, 91), short(done, 86), focus(done, 61), short(done, 56), focus(done, 31), short(done, 26), focus(done, 1)}
+	afterLongBreak := []pastSession{focus(done, 131), short(done, 126), focus(done, 101), short(done, 96), focus(done, 71), short(done, 66), focus(done, 41), {model.LongBreak, done, 26}}
+
+	tests := []struct {
+		name    string
+		history []pastSession
+		want    model.SessionType
+	}{
+		{"no history", nil, model.Focus},
+		{"after focus", []pastSession{focus(done, 1)}, model.ShortBreak},
+		{"after a break", []pastSession{focus(done, 6), short(done, 1)}, model.Focus},
+		{"long break interval", fourFocus, model.LongBreak},
+		{"skipped break", []pastSession{focus(done, 91), short(skipped, 86), focus(done, 61), short(done, 56), focus(done, 31), short(done, 26), focus(done, 1)}, model.LongBreak},
+		{"skipped focus is not counted", []pastSession{focus(done, 91), short(done, 86), focus(skipped, 61), short(done, 56), focus(done, 31), short(done, 26), focus(done, 1)}, model.ShortBreak},
+		{"after the long break", afterLongBreak, model.Focus},
+		{"cycle restarts after the long break", append(afterLongBreak, focus(done, 1)), model.ShortBreak},
+		{"idle since the last session", []pastSession{focus(done, 31)}, model.Focus},
+		{"idle gap restarts the count", []pastSession{focus(done, 151), short(done, 146), focus(done, 121), short(done, 116), focus(done, 31), short(done, 26), focus(done, 1)}, model.ShortBreak},
+		{"idle gap before a full cycle", []pastSession{focus(done, 200), short(done, 195), focus(done, 91), short(done, 86), focus(done, 61), short(done, 56), focus(done, 31), short(done, 26), focus(done, 1)}, model.LongBreak},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t)
+			user := s.signUp(t, "user")
+			s.seedHistory(t, user, tt.history)
+
+			res := s.do(t, user, "POST", "/api/v1/sessions/next", nil)
+			if res.Status != http.StatusCreated {
+				t.Fatalf("status = %d (%s), want %d", res.Status, res.Message, http.StatusCreated)
+			}
+			var session model.SessionState
+			res.decode(t, &session)
+			if session.Type != tt.want {
+				t.Errorf("type = %s, want %s", session.Type, tt.want)
+			}
+			if want := model.DefaultSettings.CycleRules().Duration(tt.want); session.Duration != want {
+				t.Errorf("duration = %d, want %d", session.Duration, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,279 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/anggara-26/pomodoro-backend.git/app/model"
+	"github.com/go-playground/validator"
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// @Summary        Create Project
+// @Description    Creates a new project for the caller
+// @Tags           Project
+// @Accept         json
+// @Produce        json
+// @Param          project body model.CreateProjectDTO true "Project Data"
+// @Success        201 {object} Response
+// @Security       BearerAuth
+// @Router         /api/v1/projects [post]
+func (h *Handler) CreateProject(c *fiber.Ctx) error {
+	b := new(model.CreateProjectDTO)
+	if err := c.BodyParser(b); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(b); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+	}
+
+	now := time.Now().UTC()
+	project := model.Project{
+		UserID:      currentUser(c).ID,
+		Name:        b.Name,
+		Description: b.Description,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err := h.models.Projects.Create(c.Context(), &project); err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(Response{
+			Message: "Failed to create project",
+			Code:    http.StatusInternalServerError,
+		})
+	}
+
+	return c.Status(http.StatusCreated).JSON(Response{
+		Message: "Project created successfully",
+		Code:    http.StatusCreated,
+		Data:    fiber.Map{"InsertedID": project.ID},
+	})
+}
+
+// @Summary        Get Projects
+// @Description    Retrieves the projects of the caller ordered by name
+// @Tags           Project
+// @Produce        json
+// @Success        200 {object} Response{data=[]model.Project}
+// @Security       BearerAuth
+// @Router         /api/v1/projects [get]
+func (h *Handler) GetProjects(c *fiber.Ctx) error {
+	projects, err := h.models.Projects.List(c.Context(), currentUser(c).ID)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(Response{
+			Message: "Failed to get projects",
+			Code:    http.StatusInternalServerError,
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(Response{
+		Message: "Projects found",
+		Code:    http.StatusOK,
+		Data:    projects,
+		Total:   int64(len(projects)),
+	})
+}
+
+// @Summary        Get Project by ID
+// @Description    Retrieves a project of the caller
+// @Tags           Project
+// @Produce        json
+// @Param          id path string true "Project ID"
+// @Success        200 {object} Response{data=model.Project}
+// @Security       BearerAuth
+// @Router         /api/v1/projects/{id} [get]
+func (h *Handler) GetProjectByID(c *fiber.Ctx) error {
+	objectID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: "Invalid ID",
+			Code:    http.StatusBadRequest,
+		})
+	}
+
+	project, err := h.models.Projects.FindByID(c.Context(), currentUser(c).ID, objectID)
+	if errors.Is(err, model.ErrNotFound) {
+		return c.Status(http.StatusNotFound).JSON(Response{
+			Message: "Project not found",
+			Code:    http.StatusNotFound,
+		})
+	}
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(Response{
+			Message: "Failed to get project",
+			Code:    http.StatusInternalServerError,
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(Response{
+		Message: "Project found",
+		Code:    http.StatusOK,
+		Data:    project,
+	})
+}
+
+// @Summary        Update Project by ID
+// @Description    Updates the name or description of a project
+// @Tags           Project
+// @Accept         json
+// @Produce        json
+// @Param          id path string true "Project ID"
+// @Param          project body model.UpdateProjectDTO true "Project Data"
+// @Success        200 {object} Response
+// @Security       BearerAuth
+// @Router         /api/v1/projects/{id} [put]
+func (h *Handler) UpdateProjectByID(c *fiber.Ctx) error {
+	b := new(model.UpdateProjectDTO)
+	if err := c.BodyParser(b); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(b); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: "Invalid ID",
+			Code:    http.StatusBadRequest,
+		})
+	}
+
+	b.UpdatedAt = time.Now().UTC()
+	err = h.models.Projects.Update(c.Context(), currentUser(c).ID, objectID, *b)
+	if errors.Is(err, model.ErrNotFound) {
+		return c.Status(http.StatusNotFound).JSON(Response{
+			Message: "Project not found",
+			Code:    http.StatusNotFound,
+		})
+	}
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(Response{
+			Message: "Failed to update project",
+			Code:    http.StatusInternalServerError,
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(Response{
+		Message: "Project updated successfully",
+		Code:    http.StatusOK,
+	})
+}
+
+// @Summary        Delete Project by ID
+// @Description    Deletes a project, its tasks are kept without a project
+// @Tags           Project
+// @Produce        json
+// @Param          id path string true "Project ID"
+// @Success        200 {object} Response
+// @Security       BearerAuth
+// @Router         /api/v1/projects/{id} [delete]
+func (h *Handler) DeleteProjectByID(c *fiber.Ctx) error {
+	objectID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: "Invalid ID",
+			Code:    http.StatusBadRequest,
+		})
+	}
+
+	err = h.models.Projects.Delete(c.Context(), currentUser(c).ID, objectID)
+	if errors.Is(err, model.ErrNotFound) {
+		return c.Status(http.StatusNotFound).JSON(Response{
+			Message: "Project not found",
+			Code:    http.StatusNotFound,
+		})
+	}
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(Response{
+			Message: "Failed to delete project",
+			Code:    http.StatusInternalServerError,
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(Response{
+		Message: "Project deleted successfully",
+		Code:    http.StatusOK,
+	})
+}
+
+// @Summary        Get Project Rollup
+// @Description    Sums up the estimated and completed pomodoros of a project's tasks and the focus minutes spent on them
+// @Tags           Project
+// @Produce        json
+// @Param          id path string true "Project ID"
+// @Success        200 {object} Response{data=model.ProjectRollup}
+// @Security       BearerAuth
+// @Router         /api/v1/projects/{id}/rollup [get]
+func (h *Handler) GetProjectRollup(c *fiber.Ctx) error {
+	objectID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: "Invalid ID",
+			Code:    http.StatusBadRequest,
+		})
+	}
+
+	userID := currentUser(c).ID
+	if err := h.checkProject(c.Context(), userID, &objectID); err != nil {
+		if errors.Is(err, errProjectNotFound) {
+			return c.Status(http.StatusNotFound).JSON(Response{
+				Message: "Project not found",
+				Code:    http.StatusNotFound,
+			})
+		}
+		return c.Status(http.StatusInternalServerError).JSON(Response{
+			Message: "Failed to get project",
+			Code:    http.StatusInternalServerError,
+		})
+	}
+
+	rollup, err := h.models.Projects.Rollup(c.Context(), userID, objectID)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(Response{
+			Message: "Failed to get project rollup",
+			Code:    http.StatusInternalServerError,
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(Response{
+		Message: "Project rollup found",
+		Code:    http.StatusOK,
+		Data:    rollup,
+	})
+}
+
+var errProjectNotFound = errors.New("project not found")
+
+// checkProject verifies that id, if set, is a project of the user.
+func (h *Handler) checkProject(ctx context.Context, userID primitive.ObjectID, id *primitive.ObjectID) error {
+	if id == nil {
+		return nil
+	}
+
+	_, err := h.models.Projects.FindByID(ctx, userID, *id)
+	if errors.Is(err, model.ErrNotFound) {
+		return errProjectNotFound
+	}
+
+	return err
+}
@@ -0,0 +1,112 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/anggara-26/pomodoro-backend.git/app/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// createProject creates a project owned by user and returns its ID.
+func (s *testServer) createProject(t *testing.T, user testUser, name string) string {
+	t.Helper()
+
+	res := s.do(t, user, "POST", "/api/v1/projects", map[string]any{"name": name})
+	if res.Status != http.StatusCreated {
+		t.Fatalf("create project: status %d: %s", res.Status, res.Message)
+	}
+
+	return res.insertedID(t)
+}
+
+// createProjectTask creates a task of project projectID and returns its ID.
+func (s *testServer) createProjectTask(t *testing.T, user testUser, projectID, title string) string {
+	t.Helper()
+
+	res := s.do(t, user, "POST", "/api/v1/tasks", map[string]any{"title": title, "estimated_pomodoros": 2, "project_id": projectID})
+	if res.Status != http.StatusCreated {
+		t.Fatalf("create task: status %d: %s", res.Status, res.Message)
+	}
+
+	return res.insertedID(t)
+}
+
+// logSession records a session of user on task id that ended now after the given minutes.
+func (s *testServer) logSession(t *testing.T, user testUser, id string, kind model.SessionType, minutes int, status model.SessionStatus) {
+	t.Helper()
+
+	userID, _ := primitive.ObjectIDFromHex(user.ID)
+	taskID, _ := primitive.ObjectIDFromHex(id)
+	now := time.Now().UTC()
+	session := model.Session{UserID: userID, TaskID: &taskID, Type: kind, Duration: int16(minutes), StartedAt: now.Add(-time.Duration(minutes) * time.Minute), Status: model.SessionActive}
+	if _, err := s.models.Sessions.Start(context.Background(), &session); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.models.Sessions.End(context.Background(), userID, session.ID, model.EndSession{EndedAt: now, Status: status}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProjectRollup(t *testing.T) {
+	s := newTestServer(t)
+	user := s.signUp(t, "user")
+	other := s.signUp(t, "other")
+	projectID := s.createProject(t, user, "Thesis")
+
+	kept := s.createProjectTask(t, user, projectID, "Kept")
+	trashed := s.createProjectTask(t, user, projectID, "Trashed")
+	loose := s.createTask(t, user, "Loose")
+	foreign := s.createTask(t, other, "Foreign")
+
+	s.logSession(t, user, kept, model.Focus, 25, model.SessionCompleted)
+	s.logSession(t, user, kept, model.ShortBreak, 5, model.SessionCompleted)
+	s.logSession(t, user, trashed, model.Focus, 10, model.SessionSkipped)
+	s.logSession(t, user, loose, model.Focus, 25, model.SessionCompleted)
+	s.logSession(t, other, foreign, model.Focus, 25, model.SessionCompleted)
+	if res := s.do(t, user, "DELETE", "/api/v1/tasks/"+trashed, nil); res.Status != http.StatusOK {
+		t.Fatalf("delete: status %d: %s", res.Status, res.Message)
+	}
+
+	res := s.do(t, user, "GET", "/api/v1/projects/"+projectID+"/rollup", nil)
+	if res.Status != http.StatusOK {
+		t.Fatalf("status = %d (%s), want %d", res.Status, res.Message, http.StatusOK)
+	}
+	var rollup model.ProjectRollup
+	res.decode(t, &rollup)
+
+	// Trashed tasks drop out of the counts but their focus time stays.
+	want := model.ProjectRollup{Tasks: 1, EstimatedPomodoros: 2, CompletedPomodoros: 1}
+	if rollup.Tasks != want.Tasks || rollup.CompletedTasks != want.CompletedTasks || rollup.EstimatedPomodoros != want.EstimatedPomodoros || rollup.CompletedPomodoros != want.CompletedPomodoros {
+		t.Errorf("rollup = %+v, want counts of %+v", rollup, want)
+	}
+	if rollup.FocusMinutes < 34.9 || rollup.FocusMinutes > 35.1 {
+		t.Errorf("focus minutes = %v, want 35", rollup.FocusMinutes)
+	}
+
+	if res := s.do(t, other, "GET", "/api/v1/projects/"+projectID+"/rollup", nil); res.Status != http.StatusNotFound {
+		t.Errorf("foreign rollup: status = %d, want %d", res.Status, http.StatusNotFound)
+	}
+}
+
+func TestDeleteProjectDetachesTasks(t *testing.T) {
+	s := newTestServer(t)
+	user := s.signUp(t, "user")
+	projectID := s.createProject(t, user, "Thesis")
+	id := s.createProjectTask(t, user, projectID, "Task")
+
+	if res := s.do(t, user, "DELETE", "/api/v1/projects/"+projectID, nil); res.Status != http.StatusOK {
+		t.Fatalf("delete: status %d: %s", res.Status, res.Message)
+	}
+	if res := s.do(t, user, "DELETE", "/api/v1/projects/"+projectID, nil); res.Status != http.StatusNotFound {
+		t.Errorf("delete again: status = %d, want %d", res.Status, http.StatusNotFound)
+	}
+
+	var task model.Task
+	s.do(t, user, "GET", "/api/v1/tasks/"+id, nil).decode(t, &task)
+	if task.ProjectID != nil {
+		t.Errorf("project_id = %s, want it unset", task.ProjectID.Hex())
+	}
+}
@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/anggara-26/pomodoro-backend.git/app/model"
+	"github.com/gofiber/fiber/v2"
+)
+
+// @Summary        Get Daily Stats
+// @Description    Aggregates the sessions of the caller per local day, defaults to the last 30 days, spans at most 366 days
+// @Tags           Stats
+// @Produce        json
+// @Param          tz query string false "IANA time zone, defaults to the settings of the caller"
+// @Param          start_date query string false "Start Date"
+// @Param          end_date query string false "End Date"
+// @Success        200 {object} Response{data=[]model.StatsBucket}
+// @Security       BearerAuth
+// @Router         /api/v1/stats/daily [get]
+func (h *Handler) GetDailyStats(c *fiber.Ctx) error {
+	return h.getStats(c, model.StatsDaily)
+}
+
+// @Summary        Get Weekly Stats
+// @Description    Aggregates the sessions of the caller per ISO week, defaults to the last 12 weeks, spans at most 104 weeks
+// @Tags           Stats
+// @Produce        json
+// @Param          tz query string false "IANA time zone, defaults to the settings of the caller"
+// @Param          start_date query string false "Start Date"
+// @Param          end_date query string false "End Date"
+// @Success        200 {object} Response{data=[]model.StatsBucket}
+// @Security       BearerAuth
+// @Router         /api/v1/stats/weekly [get]
+func (h *Handler) GetWeeklyStats(c *fiber.Ctx) error {
+	return h.getStats(c, model.StatsWeekly)
+}
+
+// @Summary        Get Monthly Stats
+// @Description    Aggregates the sessions of the caller per month, defaults to the last 12 months, spans at most 60 months
+// @Tags           Stats
+// @Produce        json
+// @Param          tz query string false "IANA time zone, defaults to the settings of the caller"
+// @Param          start_date query string false "Start Date"
+// @Param          end_date query string false "End Date"
+// @Success        200 {object} Response{data=[]model.StatsBucket}
+// @Security       BearerAuth
+// @Router         /api/v1/stats/monthly [get]
+func (h *Handler) GetMonthlyStats(c *fiber.Ctx) error {
+	return h.getStats(c, model.StatsMonthly)
+}
+
+func (h *Handler) getStats(c *fiber.Ctx, period model.StatsPeriod) error {
+	tz := c.Query("tz", currentUser(c).Settings.WithDefaults().Timezone)
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: "Invalid time zone",
+			Code:    http.StatusBadRequest,
+		})
+	}
+
+	end := time.Now().UTC()
+	if endDate := c.Query("end_date"); endDate != "" {
+		end, err = time.Parse(time.RFC3339, endDate)
+		if err != nil {
+			return c.Status(http.StatusBadRequest).JSON(Response{
+				Message: "Invalid end date format",
+				Code:    http.StatusBadRequest,
+			})
+		}
+	}
+	start := defaultStatsStart(period, end.In(loc))
+	if startDate := c.Query("start_date"); startDate != "" {
+		start, err = time.Parse(time.RFC3339, startDate)
+		if err != nil {
+			return c.Status(http.StatusBadRequest).JSON(Response{
+				Message: "Invalid start date format",
+				Code:    http.StatusBadRequest,
+			})
+		}
+	}
+	if !start.Before(end) {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: "Start date must be before end date",
+			Code:    http.StatusBadRequest,
+		})
+	}
+	if end.After(period.MaxEnd(start)) {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: "Date range is too long for " + string(period) + " stats",
+			Code:    http.StatusBadRequest,
+		})
+	}
+
+	buckets, err := h.models.Sessions.Stats(c.Context(), model.StatsQuery{
+		UserID:   currentUser(c).ID,
+		Period:   period,
+		Location: loc,
+		Start:    start,
+		End:      end,
+	})
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(Response{
+			Message: "Failed to get stats",
+			Code:    http.StatusInternalServerError,
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(Response{
+		Message: "Stats found",
+		Code:    http.StatusOK,
+		Data:    fillStats(period, start.In(loc), end.In(loc), buckets),
+	})
+}
+
+// defaultStatsStart returns the local start of the default window ending at end.
+func defaultStatsStart(period model.StatsPeriod, end time.Time) time.Time {
+	day := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, end.Location())
+
+	switch period {
+	case model.StatsWeekly:
+		monday := day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+		return monday.AddDate(0, 0, -7*11)
+	case model.StatsMonthly:
+		return time.Date(end.Year(), end.Month()-11, 1, 0, 0, 0, 0, end.Location())
+	default:
+		return day.AddDate(0, 0, -29)
+	}
+}
+
+// fillStats adds an empty bucket for every period between start and end that
+// has no sessions, so clients can chart the result as is.
+func fillStats(period model.StatsPeriod, start, end time.Time, buckets []model.StatsBucket) []model.StatsBucket {
+	seen := make(map[string]bool, len(buckets))
+	for _, b := range buckets {
+		seen[b.Period] = true
+	}
+
+	for t := start; t.Before(end); t = t.AddDate(0, 0, 1) {
+		key := period.Key(t)
+		if !seen[key] {
+			seen[key] = true
+			buckets = append(buckets, model.NewStatsBucket(key))
+		}
+	}
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i].Period < buckets[j].Period })
+
+	return buckets
+}
@@ -0,0 +1,133 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/anggara-26/pomodoro-backend.git/app/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func statsPath(period, start, end string) string {
+	return "/api/v1/stats/" + period + "?" + url.Values{"tz": {"UTC"}, "start_date": {start}, "end_date": {end}}.Encode()
+}
+
+func TestStatsRange(t *testing.T) {
+	tests := []struct {
+		period string
+		start  string
+		end    string
+		want   int
+	}{
+		{"daily", "2026-01-01T00:00:00Z", "2027-01-02T00:00:00Z", http.StatusOK},
+		{"daily", "2026-01-01T00:00:00Z", "2027-01-02T00:00:01Z", http.StatusBadRequest},
+		{"daily", "0001-01-01T00:00:00Z", "2026-01-01T00:00:00Z", http.StatusBadRequest},
+		{"weekly", "2026-01-01T00:00:00Z", "2027-12-30T00:00:00Z", http.StatusOK},
+		{"weekly", "2026-01-01T00:00:00Z", "2028-01-01T00:00:00Z", http.StatusBadRequest},
+		{"monthly", "2026-01-01T00:00:00Z", "2031-01-01T00:00:00Z", http.StatusOK},
+		{"monthly", "2026-01-01T00:00:00Z", "2031-02-01T00:00:00Z", http.StatusBadRequest},
+		{"daily", "2026-01-02T00:00:00Z", "2026-01-01T00:00:00Z", http.StatusBadRequest},
+	}
+
+	s := newTestServer(t)
+	user := s.signUp(t, "user")
+	for _, tt := range tests {
+		t.Run(tt.period+" "+tt.start+" to "+tt.end, func(t *testing.T) {
+			res := s.do(t, user, "GET", statsPath(tt.period, tt.start, tt.end), nil)
+			if res.Status != tt.want {
+				t.Errorf("status = %d (%s), want %d", res.Status, res.Message, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatsBuckets(t *testing.T) {
+	s := newTestServer(t)
+	user := s.signUp(t, "user")
+	userID, err := primitive.ObjectIDFromHex(user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	// A 25 minute focus session on Tuesday 3 March 2026.
+	started := time.Date(2026, 3, 3, 9, 0, 0, 0, time.UTC)
+	session := model.Session{UserID: userID, StartedAt: started, Duration: 25, Type: model.Focus, Status: model.SessionActive}
+	if _, err := s.models.Sessions.Start(ctx, &session); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.models.Sessions.End(ctx, userID, session.ID, model.EndSession{EndedAt: started.Add(25 * time.Minute), Status: model.SessionCompleted}); err != nil {
+		t.Fatal(err)
+	}
+
+	// One task completed on Thursday, and one completed before completed_at
+	// was recorded, which counts on its last update on Monday 9 March.
+	completedAt := time.Date(2026, 3, 5, 10, 0, 0, 0, time.UTC)
+	tasks := []model.Task{
+		{UserID: userID, Title: "Done", Status: model.TaskCompleted, CompletedAt: &completedAt, UpdatedAt: completedAt},
+		{UserID: userID, Title: "Done long ago", Status: model.TaskCompleted, UpdatedAt: time.Date(2026, 3, 9, 8, 0, 0, 0, time.UTC)},
+	}
+	for i := range tasks {
+		if err := s.models.Tasks.Create(ctx, &tasks[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	type bucket struct {
+		period string
+		focus  float64
+		tasks  int64
+	}
+	tests := []struct {
+		period string
+		start  string
+		end    string
+		want   []bucket
+	}{
+		{
+			period: "daily",
+			start:  "2026-03-03T00:00:00Z",
+			end:    "2026-03-06T00:00:00Z",
+			want:   []bucket{{"2026-03-03", 25, 0}, {"2026-03-04", 0, 0}, {"2026-03-05", 0, 1}},
+		},
+		{
+			period: "weekly",
+			start:  "2026-02-23T00:00:00Z",
+			end:    "2026-03-16T00:00:00Z",
+			want:   []bucket{{"2026-W09", 0, 0}, {"2026-W10", 25, 1}, {"2026-W11", 0, 1}},
+		},
+		{
+			period: "monthly",
+			start:  "2026-01-01T00:00:00Z",
+			end:    "2026-04-01T00:00:00Z",
+			want:   []bucket{{"2026-01", 0, 0}, {"2026-02", 0, 0}, {"2026-03", 25, 2}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.period, func(t *testing.T) {
+			res := s.do(t, user, "GET", statsPath(tt.period, tt.start, tt.end), nil)
+			if res.Status != http.StatusOK {
+				t.Fatalf("status = %d (%s), want %d", res.Status, res.Message, http.StatusOK)
+			}
+			var got []model.StatsBucket
+			res.decode(t, &got)
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("buckets = %+v, want %+v", got, tt.want)
+			}
+			for i, want := range tt.want {
+				b := got[i]
+				if b.Period != want.period || b.FocusMinutes != want.focus || b.TasksCompleted != want.tasks {
+					t.Errorf("bucket %d = %+v, want %+v", i, b, want)
+				}
+				if completed := b.Sessions[model.Focus].Completed; completed != int64(want.focus/25) {
+					t.Errorf("bucket %s focus sessions = %d, want %d", b.Period, completed, int64(want.focus/25))
+				}
+			}
+		})
+	}
+}
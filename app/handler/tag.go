@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/anggara-26/pomodoro-backend.git/app/model"
+	"github.com/go-playground/validator"
+	"github.com/gofiber/fiber/v2"
+)
+
+// @Summary        Get Tags
+// @Description    Retrieves the tags on the caller's tasks with how many tasks use them, most used first
+// @Tags           Tag
+// @Produce        json
+// @Success        200 {object} Response{data=[]model.TagCount}
+// @Security       BearerAuth
+// @Router         /api/v1/tags [get]
+func (h *Handler) GetTags(c *fiber.Ctx) error {
+	tags, err := h.models.Tasks.Tags(c.Context(), currentUser(c).ID)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(Response{
+			Message: "Failed to get tags",
+			Code:    http.StatusInternalServerError,
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(Response{
+		Message: "Tags found",
+		Code:    http.StatusOK,
+		Data:    tags,
+		Total:   int64(len(tags)),
+	})
+}
+
+// @Summary        Rename Tag
+// @Description    Renames a tag on all of the caller's tasks, merging it into the new name where that tag already exists
+// @Tags           Tag
+// @Accept         json
+// @Produce        json
+// @Param          tag body model.RenameTagDTO true "Tag Rename"
+// @Success        200 {object} Response
+// @Security       BearerAuth
+// @Router         /api/v1/tags/rename [post]
+func (h *Handler) RenameTag(c *fiber.Ctx) error {
+	b := new(model.RenameTagDTO)
+	if err := c.BodyParser(b); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+	}
+
+	b.From = model.NormalizeTag(b.From)
+	b.To = model.NormalizeTag(b.To)
+
+	validate := validator.New()
+	if err := validate.Struct(b); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+	}
+	if b.From == b.To {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: "New tag name is the same as the old one",
+			Code:    http.StatusBadRequest,
+		})
+	}
+
+	modified, err := h.models.Tasks.RenameTag(c.Context(), currentUser(c).ID, b.From, b.To, time.Now().UTC())
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(Response{
+			Message: "Failed to rename tag",
+			Code:    http.StatusInternalServerError,
+		})
+	}
+	if modified == 0 {
+		return c.Status(http.StatusNotFound).JSON(Response{
+			Message: "Tag not found",
+			Code:    http.StatusNotFound,
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(Response{
+		Message: "Tag renamed successfully",
+		Code:    http.StatusOK,
+		Total:   modified,
+	})
+}
@@ -1,16 +1,17 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/anggara-26/pomodoro-backend.git/app/model"
-	"github.com/anggara-26/pomodoro-backend.git/db"
+	"github.com/anggara-26/pomodoro-backend.git/pkg/mergepatch"
 	"github.com/go-playground/validator"
 	"github.com/gofiber/fiber/v2"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 // @Summary        Create Task
@@ -20,8 +21,9 @@ import (
 // @Produce        json
 // @Param          task body model.CreateTaskDTO true "Task Data"
 // @Success        201 {object} Response
+// @Security       BearerAuth
 // @Router         /api/v1/tasks [post]
-func CreateTask(c *fiber.Ctx) error {
+func (h *Handler) CreateTask(c *fiber.Ctx) error {
 	b := new(model.CreateTaskDTO)
 	if err := c.BodyParser(b); err != nil {
 		return c.Status(http.StatusBadRequest).JSON(Response{
@@ -30,6 +32,9 @@ func CreateTask(c *fiber.Ctx) error {
 		})
 	}
 
+	b.UserID = currentUser(c).ID
+	b.Tags = model.NormalizeTags(b.Tags)
+
 	validate := validator.New()
 	if err := validate.Struct(b); err != nil {
 		return c.Status(http.StatusBadRequest).JSON(Response{
@@ -38,20 +43,19 @@ func CreateTask(c *fiber.Ctx) error {
 		})
 	}
 
-	collUsers := db.GetDBCollection("users")
-	count, err := collUsers.CountDocuments(c.Context(), bson.M{"_id": b.UserID})
-	if err != nil {
+	if err := h.checkProject(c.Context(), b.UserID, b.ProjectID); err != nil {
+		if errors.Is(err, errProjectNotFound) {
+			return c.Status(http.StatusBadRequest).JSON(Response{
+				Message: "Project not found",
+				Code:    http.StatusBadRequest,
+			})
+		}
 		return c.Status(http.StatusInternalServerError).JSON(Response{
-			Message: "Failed to check user_id uniqueness",
+			Message: "Failed to get project",
 			Code:    http.StatusInternalServerError,
 		})
 	}
-	if count == 0 {
-		return c.Status(http.StatusBadRequest).JSON(Response{
-			Message: "User not found",
-			Code:    http.StatusBadRequest,
-		})
-	}
+
 	if b.AssignedAt == nil {
 		assignedAt := time.Now().UTC()
 		b.AssignedAt = &assignedAt
@@ -60,23 +64,30 @@ func CreateTask(c *fiber.Ctx) error {
 		estimatedPomodoros := int16(1)
 		b.EstimatedPomodoros = &estimatedPomodoros
 	}
+	if b.Recurrence != nil {
+		b.Recurrence.Anchor(b.AssignedAt.In(currentUser(c).Settings.Location()))
+	}
 
-	task := model.CreateTaskDTO{
+	now := time.Now().UTC()
+	task := model.Task{
 		UserID:             b.UserID,
+		ProjectID:          b.ProjectID,
 		Title:              b.Title,
 		Description:        b.Description,
-		AssignedAt:         b.AssignedAt,
+		AssignedAt:         *b.AssignedAt,
+		DueAt:              b.DueAt,
+		Priority:           b.Priority,
+		Position:           model.InitialPosition(now),
 		Status:             model.TaskPending,
-		EstimatedPomodoros: b.EstimatedPomodoros,
+		EstimatedPomodoros: *b.EstimatedPomodoros,
 		CompletedPomodoros: b.CompletedPomodoros,
-		CreatedAt:          time.Now().UTC(),
-		UpdatedAt:          time.Now().UTC(),
+		Tags:               b.Tags,
+		Recurrence:         b.Recurrence,
+		CreatedAt:          now,
+		UpdatedAt:          now,
 	}
 
-	coll := db.GetDBCollection("tasks")
-
-	result, err := coll.InsertOne(c.Context(), task)
-	if err != nil {
+	if err := h.models.Tasks.Create(c.Context(), &task); err != nil {
 		return c.Status(http.StatusInternalServerError).JSON(Response{
 			Message: "Failed to create task",
 			Code:    http.StatusInternalServerError,
@@ -86,7 +97,7 @@ func CreateTask(c *fiber.Ctx) error {
 	return c.Status(http.StatusCreated).JSON(Response{
 		Message: "Task created successfully",
 		Code:    http.StatusCreated,
-		Data:    result,
+		Data:    fiber.Map{"InsertedID": task.ID},
 	})
 }
 
@@ -96,10 +107,9 @@ func CreateTask(c *fiber.Ctx) error {
 // @Produce				json
 // @Param					id path string true "Task ID"
 // @Success				200 {object} Response
+// @Security			BearerAuth
 // @Router				/api/v1/tasks/{id} [get]
-func GetTaskByID(c *fiber.Ctx) error {
-	coll := db.GetDBCollection("tasks")
-
+func (h *Handler) GetTaskByID(c *fiber.Ctx) error {
 	id := c.Params("id")
 	if id == "" {
 		return c.Status(http.StatusBadRequest).JSON(Response{
@@ -116,8 +126,7 @@ func GetTaskByID(c *fiber.Ctx) error {
 		})
 	}
 
-	task := model.Task{}
-	err = coll.FindOne(c.Context(), bson.M{"_id": objectID}).Decode(&task)
+	task, err := h.models.Tasks.FindByID(c.Context(), currentUser(c).ID, objectID)
 	if err != nil {
 		return c.Status(http.StatusNotFound).JSON(Response{
 			Message: "Task not found",
@@ -133,15 +142,16 @@ func GetTaskByID(c *fiber.Ctx) error {
 }
 
 // @Summary				Update Task by ID
-// @Description		Updates a task in the database by ID
+// @Description		Updates a task in the database by ID. Status moves from pending to in_progress to completed, completed tasks can be reopened and deleting goes through DELETE. While checklist items carry estimates, estimated_pomodoros stays their sum
 // @Tags					Task
 // @Accept				json
 // @Produce				json
 // @Param					id path string true "Task ID"
 // @Param					task body model.UpdateTaskDTO true "Task Data"
 // @Success				200 {object} Response
+// @Security			BearerAuth
 // @Router				/api/v1/tasks/{id} [put]
-func UpdateTaskByID(c *fiber.Ctx) error {
+func (h *Handler) UpdateTaskByID(c *fiber.Ctx) error {
 	b := new(model.UpdateTaskDTO)
 	if err := c.BodyParser(b); err != nil {
 		return c.Status(http.StatusBadRequest).JSON(Response{
@@ -149,6 +159,10 @@ func UpdateTaskByID(c *fiber.Ctx) error {
 			Code:    http.StatusBadRequest,
 		})
 	}
+	if b.Tags != nil {
+		tags := model.NormalizeTags(*b.Tags)
+		b.Tags = &tags
+	}
 
 	validate := validator.New()
 	if err := validate.Struct(b); err != nil {
@@ -174,19 +188,193 @@ func UpdateTaskByID(c *fiber.Ctx) error {
 		})
 	}
 
+	if err := h.checkProject(c.Context(), currentUser(c).ID, b.ProjectID); err != nil {
+		if errors.Is(err, errProjectNotFound) {
+			return c.Status(http.StatusBadRequest).JSON(Response{
+				Message: "Project not found",
+				Code:    http.StatusBadRequest,
+			})
+		}
+		return c.Status(http.StatusInternalServerError).JSON(Response{
+			Message: "Failed to get project",
+			Code:    http.StatusInternalServerError,
+		})
+	}
+
+	if b.Recurrence != nil {
+		assignedAt := b.AssignedAt
+		if assignedAt == nil {
+			current, err := h.models.Tasks.FindByID(c.Context(), currentUser(c).ID, objectID)
+			if errors.Is(err, model.ErrNotFound) {
+				return c.Status(http.StatusNotFound).JSON(Response{
+					Message: "Task not found",
+					Code:    http.StatusNotFound,
+				})
+			}
+			if err != nil {
+				return c.Status(http.StatusInternalServerError).JSON(Response{
+					Message: "Failed to get task",
+					Code:    http.StatusInternalServerError,
+				})
+			}
+			assignedAt = &current.AssignedAt
+		}
+		b.Recurrence.Anchor(assignedAt.In(currentUser(c).Settings.Location()))
+	}
+
 	task := model.UpdateTaskDTO{
+		ProjectID:          b.ProjectID,
 		Title:              b.Title,
 		Description:        b.Description,
 		AssignedAt:         b.AssignedAt,
+		DueAt:              b.DueAt,
+		Priority:           b.Priority,
 		Status:             b.Status,
 		EstimatedPomodoros: b.EstimatedPomodoros,
 		CompletedPomodoros: b.CompletedPomodoros,
+		Tags:               b.Tags,
+		Recurrence:         b.Recurrence,
 		UpdatedAt:          time.Now().UTC(),
 	}
 
-	coll := db.GetDBCollection("tasks")
+	err = h.models.Tasks.Update(c.Context(), currentUser(c).ID, objectID, task)
+	if errors.Is(err, model.ErrNotFound) {
+		return c.Status(http.StatusNotFound).JSON(Response{
+			Message: "Task not found",
+			Code:    http.StatusNotFound,
+		})
+	}
+	if errors.Is(err, model.ErrInvalidTransition) {
+		return c.Status(http.StatusConflict).JSON(Response{
+			Message: "Task cannot move to status " + string(*task.Status),
+			Code:    http.StatusConflict,
+		})
+	}
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(Response{
+			Message: "Failed to update task",
+			Code:    http.StatusInternalServerError,
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(Response{
+		Message: "Task updated successfully",
+		Code:    http.StatusOK,
+	})
+}
 
-	_, err = coll.UpdateOne(c.Context(), bson.M{"_id": objectID}, bson.M{"$set": task})
+// @Summary        Patch Task by ID
+// @Description    Applies an RFC 7396 JSON merge patch to a task and returns the updated task. A null removes the field, status follows the same transitions and estimated_pomodoros the same checklist rollup as updating a task. completed_pomodoros is only counted by ending focus sessions. Responds 412 if the task changed after If-Unmodified-Since and 409 if it changed while being patched
+// @Tags           Task
+// @Accept         json
+// @Accept         application/merge-patch+json
+// @Produce        json
+// @Param          id path string true "Task ID"
+// @Param          If-Unmodified-Since header string false "Only patch the task if it was not modified after this HTTP date"
+// @Param          patch body model.TaskPatch true "Merge patch"
+// @Success        200 {object} Response{data=model.Task}
+// @Security       BearerAuth
+// @Router         /api/v1/tasks/{id} [patch]
+func (h *Handler) PatchTaskByID(c *fiber.Ctx) error {
+	objectID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: "Invalid ID",
+			Code:    http.StatusBadRequest,
+		})
+	}
+
+	user := currentUser(c)
+	current, err := h.models.Tasks.FindByID(c.Context(), user.ID, objectID)
+	if errors.Is(err, model.ErrNotFound) || err == nil && current.Status == model.TaskDeleted {
+		return c.Status(http.StatusNotFound).JSON(Response{
+			Message: "Task not found",
+			Code:    http.StatusNotFound,
+		})
+	}
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(Response{
+			Message: "Failed to get task",
+			Code:    http.StatusInternalServerError,
+		})
+	}
+
+	// An invalid date is ignored, as RFC 9110 asks. HTTP dates have whole seconds.
+	since, err := http.ParseTime(c.Get(fiber.HeaderIfUnmodifiedSince))
+	if err == nil && current.UpdatedAt.Truncate(time.Second).After(since) {
+		return c.Status(http.StatusPreconditionFailed).JSON(Response{
+			Message: "Task was modified since " + c.Get(fiber.HeaderIfUnmodifiedSince),
+			Code:    http.StatusPreconditionFailed,
+		})
+	}
+
+	patch := current.Editable()
+	if err := mergepatch.ApplyTo(&patch, c.Body()); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+	}
+	if patch.Tags != nil {
+		patch.Tags = model.NormalizeTags(patch.Tags)
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(patch); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+	}
+	if patch.AssignedAt.IsZero() {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: "Assigned at cannot be removed",
+			Code:    http.StatusBadRequest,
+		})
+	}
+	if !current.Status.CanTransitionTo(patch.Status) {
+		return c.Status(http.StatusConflict).JSON(Response{
+			Message: "Task cannot move to status " + string(patch.Status),
+			Code:    http.StatusConflict,
+		})
+	}
+
+	if err := h.checkProject(c.Context(), user.ID, patch.ProjectID); err != nil {
+		if errors.Is(err, errProjectNotFound) {
+			return c.Status(http.StatusBadRequest).JSON(Response{
+				Message: "Project not found",
+				Code:    http.StatusBadRequest,
+			})
+		}
+		return c.Status(http.StatusInternalServerError).JSON(Response{
+			Message: "Failed to get project",
+			Code:    http.StatusInternalServerError,
+		})
+	}
+
+	if patch.Recurrence != nil {
+		patch.Recurrence.Anchor(patch.AssignedAt.In(user.Settings.Location()))
+	}
+
+	task, err := h.models.Tasks.Patch(c.Context(), user.ID, objectID, patch, current.UpdatedAt, time.Now().UTC())
+	if errors.Is(err, model.ErrNotFound) {
+		return c.Status(http.StatusNotFound).JSON(Response{
+			Message: "Task not found",
+			Code:    http.StatusNotFound,
+		})
+	}
+	if errors.Is(err, model.ErrInvalidTransition) {
+		return c.Status(http.StatusConflict).JSON(Response{
+			Message: "Task cannot move to status " + string(patch.Status),
+			Code:    http.StatusConflict,
+		})
+	}
+	if errors.Is(err, model.ErrConflict) {
+		return c.Status(http.StatusConflict).JSON(Response{
+			Message: "Task was modified concurrently, please retry",
+			Code:    http.StatusConflict,
+		})
+	}
 	if err != nil {
 		return c.Status(http.StatusInternalServerError).JSON(Response{
 			Message: "Failed to update task",
@@ -197,19 +385,19 @@ func UpdateTaskByID(c *fiber.Ctx) error {
 	return c.Status(http.StatusOK).JSON(Response{
 		Message: "Task updated successfully",
 		Code:    http.StatusOK,
+		Data:    task,
 	})
 }
 
 // @Summary				Delete Task by ID
-// @Description		Deletes a task from the database by ID
+// @Description		Moves a task to the trash, it is purged once the retention period has passed
 // @Tags					Task
 // @Produce				json
 // @Param					id path string true "Task ID"
 // @Success				200 {object} Response
+// @Security			BearerAuth
 // @Router				/api/v1/tasks/{id} [delete]
-func DeleteTaskByID(c *fiber.Ctx) error {
-	coll := db.GetDBCollection("tasks")
-
+func (h *Handler) DeleteTaskByID(c *fiber.Ctx) error {
 	id := c.Params("id")
 	if id == "" {
 		return c.Status(http.StatusBadRequest).JSON(Response{
@@ -226,12 +414,13 @@ func DeleteTaskByID(c *fiber.Ctx) error {
 		})
 	}
 
-	task := model.DeleteTaskDTO{
-		Status:    model.TaskDeleted,
-		DeletedAt: time.Now().UTC(),
+	err = h.models.Tasks.Delete(c.Context(), currentUser(c).ID, objectID, time.Now().UTC())
+	if errors.Is(err, model.ErrNotFound) {
+		return c.Status(http.StatusNotFound).JSON(Response{
+			Message: "Task not found",
+			Code:    http.StatusNotFound,
+		})
 	}
-
-	_, err = coll.UpdateOne(c.Context(), bson.M{"_id": objectID}, bson.M{"$set": task})
 	if err != nil {
 		return c.Status(http.StatusInternalServerError).JSON(Response{
 			Message: "Failed to delete task",
@@ -251,16 +440,24 @@ func DeleteTaskByID(c *fiber.Ctx) error {
 // @Produce				json
 // @Param					id path string true "User ID"
 // @Param					status query string false "Task Status"
-// @Param					title query string false "Task Title"
+// @Param					title query string false "Title prefix, matched literally and ignoring case"
+// @Param					project_id query string false "Project ID"
+// @Param					priority query int false "Priority from 0 (none) to 3 (high)"
+// @Param					due_after query string false "Due at or after"
+// @Param					due_before query string false "Due at or before"
+// @Param					sort query string false "created_at, assigned_at, due_at, priority or manual, prefixed with - for descending order" default(-created_at)
+// @Param					tags query string false "Comma separated tags"
+// @Param					tags_mode query string false "Match tasks with any or all of the tags" Enums(any, all) default(any)
+// @Param					q query string false "Full-text search over title and description, ranked by relevance"
 // @Param					start_date query string false "Start Date"
 // @Param					end_date query string false "End Date"
-// @Param					page query int false "Page number"
-// @Param					limit query int false "Number of tasks per page"
+// @Param					page query int false "Page number, ignored in cursor mode"
+// @Param					cursor query string false "Cursor from next_cursor; selects cursor mode, pass it empty for the first page"
+// @Param					limit query int false "Number of tasks per page, at most 100" default(10)
 // @Success				200 {object} Response
+// @Security			BearerAuth
 // @Router				/api/v1/tasks/user/{id} [get]
-func GetTasksByUserID(c *fiber.Ctx) error {
-	coll := db.GetDBCollection("tasks")
-
+func (h *Handler) GetTasksByUserID(c *fiber.Ctx) error {
 	id := c.Params("id")
 	if id == "" {
 		return c.Status(http.StatusBadRequest).JSON(Response{
@@ -276,15 +473,87 @@ func GetTasksByUserID(c *fiber.Ctx) error {
 			Code:    http.StatusBadRequest,
 		})
 	}
+	if objectID != currentUser(c).ID {
+		return c.Status(http.StatusNotFound).JSON(Response{
+			Message: "User not found",
+			Code:    http.StatusNotFound,
+		})
+	}
 
-	filter := bson.M{"user_id": objectID, "status": bson.M{"$ne": string(model.TaskDeleted)}}
+	filter := model.TaskFilter{
+		UserID: objectID,
+		Status: model.TaskStatus(c.Query("status")),
+		Title:  c.Query("title"),
+		Query:  c.Query("q"),
+	}
 
-	if status := c.Query("status"); status != "" {
-		filter["status"] = status
+	if projectID := c.Query("project_id"); projectID != "" {
+		projectObjectID, err := primitive.ObjectIDFromHex(projectID)
+		if err != nil {
+			return c.Status(http.StatusBadRequest).JSON(Response{
+				Message: "Invalid project ID",
+				Code:    http.StatusBadRequest,
+			})
+		}
+		filter.ProjectID = &projectObjectID
 	}
-	if title := c.Query("title"); title != "" {
-		filter["title"] = bson.M{"$regex": title, "$options": "i"}
+	if priority := c.Query("priority"); priority != "" {
+		value, err := strconv.Atoi(priority)
+		if err != nil || value < int(model.PriorityNone) || value > int(model.PriorityHigh) {
+			return c.Status(http.StatusBadRequest).JSON(Response{
+				Message: "Invalid priority",
+				Code:    http.StatusBadRequest,
+			})
+		}
+		p := model.Priority(value)
+		filter.Priority = &p
+	}
+	if dueAfter := c.Query("due_after"); dueAfter != "" {
+		after, err := time.Parse(time.RFC3339, dueAfter)
+		if err != nil {
+			return c.Status(http.StatusBadRequest).JSON(Response{
+				Message: "Invalid due after format",
+				Code:    http.StatusBadRequest,
+			})
+		}
+		filter.DueAfter = &after
+	}
+	if dueBefore := c.Query("due_before"); dueBefore != "" {
+		before, err := time.Parse(time.RFC3339, dueBefore)
+		if err != nil {
+			return c.Status(http.StatusBadRequest).JSON(Response{
+				Message: "Invalid due before format",
+				Code:    http.StatusBadRequest,
+			})
+		}
+		filter.DueBefore = &before
 	}
+	if sortBy := c.Query("sort"); sortBy != "" {
+		filter.SortBy, filter.SortDesc = strings.TrimPrefix(sortBy, "-"), strings.HasPrefix(sortBy, "-")
+		if !taskSorts[filter.SortBy] {
+			return c.Status(http.StatusBadRequest).JSON(Response{
+				Message: "Invalid sort field",
+				Code:    http.StatusBadRequest,
+			})
+		}
+		if filter.SortBy == "manual" {
+			filter.SortBy = "position"
+		}
+	}
+	if tags := c.Query("tags"); tags != "" {
+		filter.Tags = model.NormalizeTags(strings.Split(tags, ","))
+	}
+	switch c.Query("tags_mode", "any") {
+	case "any":
+	case "all":
+		filter.TagsMatchAll = true
+	default:
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: "Invalid tags mode",
+			Code:    http.StatusBadRequest,
+		})
+	}
+
 	if startDate := c.Query("start_date"); startDate != "" {
 		start, err := time.Parse(time.RFC3339, startDate)
 		if err != nil {
@@ -293,7 +562,7 @@ func GetTasksByUserID(c *fiber.Ctx) error {
 				Code:    http.StatusBadRequest,
 			})
 		}
-		filter["assigned_at"] = bson.M{"$gte": start}
+		filter.StartDate = &start
 	}
 	if endDate := c.Query("end_date"); endDate != "" {
 		end, err := time.Parse(time.RFC3339, endDate)
@@ -303,20 +572,45 @@ func GetTasksByUserID(c *fiber.Ctx) error {
 				Code:    http.StatusBadRequest,
 			})
 		}
-		if _, ok := filter["assigned_at"]; ok {
-			filter["assigned_at"].(bson.M)["$lte"] = end
-		} else {
-			filter["assigned_at"] = bson.M{"$lte": end}
-		}
+		filter.EndDate = &end
 	}
 
-	page := c.QueryInt("page", 1)
 	limit := c.QueryInt("limit", 10)
-	skip := (page - 1) * limit
+	if limit < 1 || limit > maxPageSize {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: "Invalid limit",
+			Code:    http.StatusBadRequest,
+		})
+	}
+	filter.Limit = int64(limit)
 
-	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetSkip(int64(skip)).SetLimit(int64(limit))
+	// Cursor mode is selected by the cursor parameter, an empty one fetching the first page.
+	if c.Context().QueryArgs().Has("cursor") {
+		if filter.Query != "" {
+			return c.Status(http.StatusBadRequest).JSON(Response{
+				Message: "Search results do not support cursor pagination",
+				Code:    http.StatusBadRequest,
+			})
+		}
+		if filter.SortBy != "" && (filter.SortBy != "created_at" || !filter.SortDesc) {
+			return c.Status(http.StatusBadRequest).JSON(Response{
+				Message: "Cursor pagination only supports the default sort",
+				Code:    http.StatusBadRequest,
+			})
+		}
+		return h.listTasksAfter(c, filter, c.Query("cursor"))
+	}
+
+	page := c.QueryInt("page", 1)
+	if page < 1 {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: "Invalid page",
+			Code:    http.StatusBadRequest,
+		})
+	}
+	filter.Skip = int64(page-1) * int64(limit)
 
-	cursor, err := coll.Find(c.Context(), filter, opts)
+	tasks, total, err := h.models.Tasks.List(c.Context(), filter)
 	if err != nil {
 		return c.Status(http.StatusInternalServerError).JSON(Response{
 			Message: "Failed to get tasks",
@@ -324,26 +618,170 @@ func GetTasksByUserID(c *fiber.Ctx) error {
 		})
 	}
 
-	var tasks []model.Task
-	if err := cursor.All(c.Context(), &tasks); err != nil {
+	return c.Status(http.StatusOK).JSON(Response{
+		Message: "Tasks found",
+		Code:    http.StatusOK,
+		Data:    tasks,
+		Total:   total,
+	})
+}
+
+// listTasksAfter responds with the page of tasks following the cursor token.
+func (h *Handler) listTasksAfter(c *fiber.Ctx, filter model.TaskFilter, token string) error {
+	var after *model.TaskCursor
+	if token != "" {
+		cursor, err := model.ParseTaskCursor(token)
+		if err != nil {
+			return c.Status(http.StatusBadRequest).JSON(Response{
+				Message: "Invalid cursor",
+				Code:    http.StatusBadRequest,
+			})
+		}
+		after = &cursor
+	}
+
+	// One extra task tells whether another page follows.
+	limit := filter.Limit
+	filter.Limit++
+	tasks, err := h.models.Tasks.ListAfter(c.Context(), filter, after)
+	if err != nil {
 		return c.Status(http.StatusInternalServerError).JSON(Response{
 			Message: "Failed to get tasks",
 			Code:    http.StatusInternalServerError,
 		})
 	}
 
-	total, err := coll.CountDocuments(c.Context(), filter)
+	var next string
+	if int64(len(tasks)) > limit {
+		tasks = tasks[:limit]
+		next = model.NewTaskCursor(tasks[limit-1]).String()
+	}
+
+	return c.Status(http.StatusOK).JSON(Response{
+		Message:    "Tasks found",
+		Code:       http.StatusOK,
+		Data:       tasks,
+		NextCursor: next,
+	})
+}
+
+// taskSorts are the fields tasks can be sorted by.
+var taskSorts = map[string]bool{"created_at": true, "assigned_at": true, "due_at": true, "priority": true, "manual": true}
+
+// @Summary        Get Today's Tasks
+// @Description    Retrieves the tasks assigned to or due on the caller's current date, highest priority first, with the estimated pomodoros left on them
+// @Tags           Task
+// @Produce        json
+// @Param          tz query string false "IANA time zone, defaults to the user's settings"
+// @Success        200 {object} Response{data=model.DayPlan}
+// @Security       BearerAuth
+// @Router         /api/v1/tasks/today [get]
+func (h *Handler) GetTodayTasks(c *fiber.Ctx) error {
+	user := currentUser(c)
+
+	loc := user.Settings.Location()
+	if tz := c.Query("tz"); tz != "" {
+		var err error
+		if loc, err = time.LoadLocation(tz); err != nil {
+			return c.Status(http.StatusBadRequest).JSON(Response{
+				Message: "Invalid time zone",
+				Code:    http.StatusBadRequest,
+			})
+		}
+	}
+
+	y, m, d := time.Now().In(loc).Date()
+	start := time.Date(y, m, d, 0, 0, 0, 0, loc)
+
+	tasks, err := h.models.Tasks.ListForDay(c.Context(), user.ID, start.UTC(), start.AddDate(0, 0, 1).UTC())
 	if err != nil {
 		return c.Status(http.StatusInternalServerError).JSON(Response{
-			Message: "Failed to count tasks",
+			Message: "Failed to get tasks",
 			Code:    http.StatusInternalServerError,
 		})
 	}
 
+	plan := model.DayPlan{
+		Date:  start.Format("2006-01-02"),
+		Tasks: tasks,
+	}
+	if plan.Tasks == nil {
+		plan.Tasks = []model.Task{}
+	}
+	for _, task := range tasks {
+		plan.RemainingPomodoros += int64(task.RemainingPomodoros())
+	}
+
 	return c.Status(http.StatusOK).JSON(Response{
 		Message: "Tasks found",
 		Code:    http.StatusOK,
-		Data:    tasks,
-		Total:   total,
+		Data:    plan,
+		Total:   int64(len(tasks)),
+	})
+}
+
+// @Summary        Move Task
+// @Description    Places a task right before before_id, right after after_id, or between the two, for the manual sort order
+// @Tags           Task
+// @Accept         json
+// @Produce        json
+// @Param          id path string true "Task ID"
+// @Param          move body model.MoveTaskDTO true "Neighbors"
+// @Success        200 {object} Response{data=model.Task}
+// @Security       BearerAuth
+// @Router         /api/v1/tasks/{id}/move [patch]
+func (h *Handler) MoveTask(c *fiber.Ctx) error {
+	objectID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: "Invalid ID",
+			Code:    http.StatusBadRequest,
+		})
+	}
+
+	b := new(model.MoveTaskDTO)
+	if err := c.BodyParser(b); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+	}
+	if b.BeforeID == nil && b.AfterID == nil {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: "Either before_id or after_id is required",
+			Code:    http.StatusBadRequest,
+		})
+	}
+	if b.BeforeID != nil && *b.BeforeID == objectID || b.AfterID != nil && *b.AfterID == objectID {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: "A task cannot be moved next to itself",
+			Code:    http.StatusBadRequest,
+		})
+	}
+
+	task, err := h.models.Tasks.Move(c.Context(), currentUser(c).ID, objectID, *b, time.Now().UTC())
+	if errors.Is(err, model.ErrNotFound) {
+		return c.Status(http.StatusNotFound).JSON(Response{
+			Message: "Task or neighbor not found",
+			Code:    http.StatusNotFound,
+		})
+	}
+	if errors.Is(err, model.ErrConflict) {
+		return c.Status(http.StatusConflict).JSON(Response{
+			Message: "Tasks were moved meanwhile",
+			Code:    http.StatusConflict,
+		})
+	}
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(Response{
+			Message: "Failed to move task",
+			Code:    http.StatusInternalServerError,
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(Response{
+		Message: "Task moved successfully",
+		Code:    http.StatusOK,
+		Data:    task,
 	})
 }
@@ -0,0 +1,298 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/anggara-26/pomodoro-backend.git/app/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestTaskOwnership(t *testing.T) {
+	s := newTestServer(t)
+	owner := s.signUp(t, "owner")
+	other := s.signUp(t, "other")
+	taskID := s.createTask(t, owner, "Write report")
+	neighborID := s.createTask(t, owner, "Review report")
+	trashedID := s.createTask(t, owner, "Old idea")
+	if res := s.do(t, owner, "DELETE", "/api/v1/tasks/"+trashedID, nil); res.Status != http.StatusOK {
+		t.Fatalf("delete: status %d: %s", res.Status, res.Message)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   any
+	}{
+		{"get", "GET", "/api/v1/tasks/" + taskID, nil},
+		{"update", "PUT", "/api/v1/tasks/" + taskID, map[string]any{"title": "Mine now"}},
+		{"patch", "PATCH", "/api/v1/tasks/" + taskID, map[string]any{"title": "Mine now"}},
+		{"delete", "DELETE", "/api/v1/tasks/" + taskID, nil},
+		{"restore", "POST", "/api/v1/tasks/" + trashedID + "/restore", nil},
+		{"move", "PATCH", "/api/v1/tasks/" + taskID + "/move", map[string]any{"after_id": neighborID}},
+		{"add checklist item", "POST", "/api/v1/tasks/" + taskID + "/checklist", map[string]any{"title": "Outline"}},
+		{"list", "GET", "/api/v1/tasks/user/" + owner.ID, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := s.do(t, other, tt.method, tt.path, tt.body)
+			if res.Status != http.StatusNotFound {
+				t.Errorf("status = %d (%s), want %d", res.Status, res.Message, http.StatusNotFound)
+			}
+		})
+	}
+
+	// None of the attempts above may have changed the tasks of the owner.
+	var task model.Task
+	s.do(t, owner, "GET", "/api/v1/tasks/"+taskID, nil).decode(t, &task)
+	if task.Title != "Write report" || task.Status != model.TaskPending || len(task.Checklist) != 0 {
+		t.Errorf("task = %+v, want it untouched", task)
+	}
+
+	trash := s.do(t, owner, "GET", "/api/v1/tasks/trash", nil)
+	if trash.Total != 1 {
+		t.Errorf("trash total = %d, want 1", trash.Total)
+	}
+	if res := s.do(t, other, "GET", "/api/v1/tasks/trash", nil); res.Total != 0 {
+		t.Errorf("trash of other user total = %d, want 0", res.Total)
+	}
+
+	bulk := s.do(t, other, "POST", "/api/v1/tasks/bulk", map[string]any{"ids": []string{taskID}, "operation": "delete"})
+	var results []model.BulkResult
+	bulk.decode(t, &results)
+	if bulk.Status != http.StatusOK || len(results) != 1 || results[0].OK {
+		t.Errorf("bulk delete of another user's task = %d %+v, want a failed result", bulk.Status, results)
+	}
+}
+
+func TestTaskStatusTransitions(t *testing.T) {
+	tests := []struct {
+		from model.TaskStatus
+		to   model.TaskStatus
+		want int
+	}{
+		{model.TaskPending, model.TaskPending, http.StatusOK},
+		{model.TaskPending, model.TaskInProgress, http.StatusOK},
+		{model.TaskPending, model.TaskCompleted, http.StatusConflict},
+		{model.TaskInProgress, model.TaskPending, http.StatusOK},
+		{model.TaskInProgress, model.TaskCompleted, http.StatusOK},
+		{model.TaskCompleted, model.TaskInProgress, http.StatusOK},
+		{model.TaskCompleted, model.TaskPending, http.StatusOK},
+		{model.TaskPending, model.TaskDeleted, http.StatusBadRequest},
+		{model.TaskPending, "archived", http.StatusBadRequest},
+		{model.TaskDeleted, model.TaskPending, http.StatusNotFound},
+	}
+
+	// steps lists the requests that take a new task to a status.
+	steps := map[model.TaskStatus][]model.TaskStatus{
+		model.TaskPending:    nil,
+		model.TaskInProgress: {model.TaskInProgress},
+		model.TaskCompleted:  {model.TaskInProgress, model.TaskCompleted},
+	}
+
+	for _, method := range []string{"PUT", "PATCH"} {
+		for _, tt := range tests {
+			t.Run(method+" "+string(tt.from)+" to "+string(tt.to), func(t *testing.T) {
+				s := newTestServer(t)
+				user := s.signUp(t, "user")
+				id := s.createTask(t, user, "Task")
+				path := "/api/v1/tasks/" + id
+
+				if tt.from == model.TaskDeleted {
+					s.do(t, user, "DELETE", path, nil)
+				}
+				for _, status := range steps[tt.from] {
+					if res := s.do(t, user, "PUT", path, map[string]any{"status": status}); res.Status != http.StatusOK {
+						t.Fatalf("move to %s: status %d: %s", status, res.Status, res.Message)
+					}
+				}
+
+				res := s.do(t, user, method, path, map[string]any{"status": tt.to})
+				if res.Status != tt.want {
+					t.Fatalf("status = %d (%s), want %d", res.Status, res.Message, tt.want)
+				}
+
+				var task model.Task
+				s.do(t, user, "GET", path, nil).decode(t, &task)
+
+				want := tt.from
+				if tt.want == http.StatusOK {
+					want = tt.to
+				}
+				if task.Status != want {
+					t.Errorf("task status = %s, want %s", task.Status, want)
+				}
+				if completed := task.CompletedAt != nil; completed != (task.Status == model.TaskCompleted) {
+					t.Errorf("completed_at = %v with status %s", task.CompletedAt, task.Status)
+				}
+			})
+		}
+	}
+}
+
+func TestRestoreTask(t *testing.T) {
+	s := newTestServer(t)
+	user := s.signUp(t, "user")
+	id := s.createTask(t, user, "Task")
+	path := "/api/v1/tasks/" + id
+
+	s.do(t, user, "PUT", path, map[string]any{"status": model.TaskInProgress})
+	s.do(t, user, "DELETE", path, nil)
+
+	if res := s.do(t, user, "DELETE", path, nil); res.Status != http.StatusNotFound {
+		t.Errorf("deleting a trashed task: status = %d, want %d", res.Status, http.StatusNotFound)
+	}
+
+	res := s.do(t, user, "POST", path+"/restore", nil)
+	if res.Status != http.StatusOK {
+		t.Fatalf("restore: status %d: %s", res.Status, res.Message)
+	}
+	var task model.Task
+	res.decode(t, &task)
+	if task.Status != model.TaskInProgress || task.DeletedAt != nil {
+		t.Errorf("restored task = %+v, want it in progress again", task)
+	}
+
+	if res := s.do(t, user, "POST", path+"/restore", nil); res.Status != http.StatusNotFound {
+		t.Errorf("restoring a live task: status = %d, want %d", res.Status, http.StatusNotFound)
+	}
+}
+
+func TestPatchTask(t *testing.T) {
+	tests := []struct {
+		name  string
+		patch map[string]any
+		want  int
+		check func(t *testing.T, task model.Task)
+	}{
+		{
+			name:  "null removes fields",
+			patch: map[string]any{"description": nil, "due_at": nil, "tags": nil},
+			want:  http.StatusOK,
+			check: func(t *testing.T, task model.Task) {
+				if task.Description != nil || task.DueAt != nil || len(task.Tags) != 0 {
+					t.Errorf("task = %+v, want description, due date and tags removed", task)
+				}
+				if task.Title != "Task" || task.Recurrence == nil {
+					t.Errorf("task = %+v, want the other fields kept", task)
+				}
+			},
+		},
+		{
+			name:  "nested recurrence is merged",
+			patch: map[string]any{"recurrence": map[string]any{"weekdays": []int{5}}},
+			want:  http.StatusOK,
+			check: func(t *testing.T, task model.Task) {
+				if task.Recurrence == nil || task.Recurrence.Frequency != model.RecurWeekly || !slices.Equal(task.Recurrence.Weekdays, []time.Weekday{time.Friday}) {
+					t.Errorf("recurrence = %+v, want weekly on fridays", task.Recurrence)
+				}
+			},
+		},
+		{
+			name:  "null recurrence stops the series",
+			patch: map[string]any{"recurrence": nil},
+			want:  http.StatusOK,
+			check: func(t *testing.T, task model.Task) {
+				if task.Recurrence != nil {
+					t.Errorf("recurrence = %+v, want none", task.Recurrence)
+				}
+			},
+		},
+		{
+			name:  "nested recurrence is validated",
+			patch: map[string]any{"recurrence": map[string]any{"frequency": "yearly"}},
+			want:  http.StatusBadRequest,
+		},
+		{
+			name:  "title cannot be removed",
+			patch: map[string]any{"title": nil},
+			want:  http.StatusBadRequest,
+		},
+		{
+			name:  "assigned date cannot be removed",
+			patch: map[string]any{"assigned_at": nil},
+			want:  http.StatusBadRequest,
+		},
+		{
+			// Only ending focus sessions counts pomodoros.
+			name:  "completed pomodoros are not patchable",
+			patch: map[string]any{"completed_pomodoros": 5},
+			want:  http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t)
+			user := s.signUp(t, "user")
+			path := "/api/v1/tasks/" + s.createTask(t, user, "Task")
+			setup := map[string]any{
+				"description": "Notes",
+				"due_at":      time.Now().Add(24 * time.Hour).UTC(),
+				"tags":        []string{"thesis"},
+				"recurrence":  map[string]any{"frequency": model.RecurWeekly, "weekdays": []int{1, 3}},
+			}
+			if res := s.do(t, user, "PATCH", path, setup); res.Status != http.StatusOK {
+				t.Fatalf("setup: status %d: %s", res.Status, res.Message)
+			}
+
+			res := s.do(t, user, "PATCH", path, tt.patch)
+			if res.Status != tt.want {
+				t.Fatalf("status = %d (%s), want %d", res.Status, res.Message, tt.want)
+			}
+			if tt.check == nil {
+				return
+			}
+			var task model.Task
+			res.decode(t, &task)
+			tt.check(t, task)
+		})
+	}
+}
+
+func TestPatchTaskPreconditions(t *testing.T) {
+	s := newTestServer(t)
+	user := s.signUp(t, "user")
+	id := s.createTask(t, user, "Task")
+	path := "/api/v1/tasks/" + id
+
+	tests := []struct {
+		name  string
+		since string
+		want  int
+	}{
+		{"modified since", time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat), http.StatusPreconditionFailed},
+		{"unmodified since", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat), http.StatusOK},
+		{"invalid date is ignored", "yesterday", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := s.doWithHeader(t, user, "PATCH", path, map[string]any{"title": tt.name}, map[string]string{"If-Unmodified-Since": tt.since})
+			if res.Status != tt.want {
+				t.Fatalf("status = %d (%s), want %d", res.Status, res.Message, tt.want)
+			}
+		})
+	}
+
+	// A change between reading and patching the task is a conflict, which
+	// the handler answers with 409.
+	var current model.Task
+	s.do(t, user, "GET", path, nil).decode(t, &current)
+	stale := current.UpdatedAt
+	if res := s.do(t, user, "PATCH", path, map[string]any{"title": "Changed"}); res.Status != http.StatusOK {
+		t.Fatalf("patch: status %d: %s", res.Status, res.Message)
+	}
+
+	userID, _ := primitive.ObjectIDFromHex(user.ID)
+	_, err := s.models.Tasks.Patch(context.Background(), userID, current.ID, current.Editable(), stale, time.Now().UTC())
+	if !errors.Is(err, model.ErrConflict) {
+		t.Errorf("Patch() with a stale time error = %v, want ErrConflict", err)
+	}
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/anggara-26/pomodoro-backend.git/app/model"
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// @Summary        Get Trash
+// @Description    Retrieves the caller's deleted tasks, most recently deleted first, until they are purged
+// @Tags           Task
+// @Produce        json
+// @Param          page query int false "Page number"
+// @Param          limit query int false "Number of tasks per page, at most 100" default(10)
+// @Success        200 {object} Response{data=[]model.Task}
+// @Security       BearerAuth
+// @Router         /api/v1/tasks/trash [get]
+func (h *Handler) GetTrashTasks(c *fiber.Ctx) error {
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 10)
+	if page < 1 || limit < 1 || limit > maxPageSize {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: "Invalid pagination",
+			Code:    http.StatusBadRequest,
+		})
+	}
+
+	tasks, total, err := h.models.Tasks.List(c.Context(), model.TaskFilter{
+		UserID:   currentUser(c).ID,
+		Status:   model.TaskDeleted,
+		SortBy:   "deleted_at",
+		SortDesc: true,
+		Skip:     int64(page-1) * int64(limit),
+		Limit:    int64(limit),
+	})
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(Response{
+			Message: "Failed to get tasks",
+			Code:    http.StatusInternalServerError,
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(Response{
+		Message: "Tasks found",
+		Code:    http.StatusOK,
+		Data:    tasks,
+		Total:   total,
+	})
+}
+
+// @Summary        Restore Task
+// @Description    Moves a task out of the trash, back to the status it had before it was deleted
+// @Tags           Task
+// @Produce        json
+// @Param          id path string true "Task ID"
+// @Success        200 {object} Response{data=model.Task}
+// @Security       BearerAuth
+// @Router         /api/v1/tasks/{id}/restore [post]
+func (h *Handler) RestoreTaskByID(c *fiber.Ctx) error {
+	objectID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: "Invalid ID",
+			Code:    http.StatusBadRequest,
+		})
+	}
+
+	task, err := h.models.Tasks.Restore(c.Context(), currentUser(c).ID, objectID, time.Now().UTC())
+	if errors.Is(err, model.ErrNotFound) {
+		return c.Status(http.StatusNotFound).JSON(Response{
+			Message: "Task not found in trash",
+			Code:    http.StatusNotFound,
+		})
+	}
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(Response{
+			Message: "Failed to restore task",
+			Code:    http.StatusInternalServerError,
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(Response{
+		Message: "Task restored successfully",
+		Code:    http.StatusOK,
+		Data:    task,
+	})
+}
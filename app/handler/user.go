@@ -1,26 +1,29 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"time"
 
 	"github.com/anggara-26/pomodoro-backend.git/app/model"
-	"github.com/anggara-26/pomodoro-backend.git/db"
+	"github.com/anggara-26/pomodoro-backend.git/pkg/auth"
+	"github.com/anggara-26/pomodoro-backend.git/pkg/mergepatch"
+	"github.com/anggara-26/pomodoro-backend.git/pkg/middleware"
 	"github.com/go-playground/validator"
 	"github.com/gofiber/fiber/v2"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // @Summary        Create User
-// @Description    Creates a new user in the database
+// @Description    Creates a new user in the database for the Firebase account of the caller
 // @Tags           User
 // @Accept         json
 // @Produce        json
 // @Param          user body model.CreateUserDTO true "User Data"
 // @Success        201 {object} Response
+// @Security       BearerAuth
 // @Router         /api/v1/users [post]
-func CreateUser(c *fiber.Ctx) error {
+func (h *Handler) CreateUser(c *fiber.Ctx) error {
 	b := new(model.CreateUserDTO)
 	if err := c.BodyParser(b); err != nil {
 		return c.Status(http.StatusBadRequest).JSON(Response{
@@ -29,6 +32,8 @@ func CreateUser(c *fiber.Ctx) error {
 		})
 	}
 
+	b.FirebaseUID = c.Locals(middleware.TokenKey).(*auth.Token).UID
+
 	validate := validator.New()
 	if err := validate.Struct(b); err != nil {
 		return c.Status(http.StatusBadRequest).JSON(Response{
@@ -37,25 +42,36 @@ func CreateUser(c *fiber.Ctx) error {
 		})
 	}
 
-	coll := db.GetDBCollection("users")
-
-	count, err := coll.CountDocuments(c.Context(), bson.M{"firebase_uid": b.FirebaseUID})
-	if err != nil {
+	_, err := h.models.Users.FindByFirebaseUID(c.Context(), b.FirebaseUID)
+	if err == nil {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: "FirebaseUID already exists",
+			Code:    http.StatusBadRequest,
+		})
+	}
+	if !errors.Is(err, model.ErrNotFound) {
 		return c.Status(http.StatusInternalServerError).JSON(Response{
 			Message: "Failed to check firebase_uid uniqueness",
 			Code:    http.StatusInternalServerError,
 		})
 	}
-	if count > 0 {
+
+	user := model.User{
+		FirebaseUID: b.FirebaseUID,
+		Email:       b.Email,
+		Name:        b.Name,
+		Settings:    model.DefaultSettings,
+		CreatedAt:   time.Now().UTC(),
+	}
+
+	// The lookup above races with concurrent signups, the unique index has the last word.
+	err = h.models.Users.Create(c.Context(), &user)
+	if errors.Is(err, model.ErrConflict) {
 		return c.Status(http.StatusBadRequest).JSON(Response{
 			Message: "FirebaseUID already exists",
 			Code:    http.StatusBadRequest,
 		})
 	}
-
-	b.CreatedAt = time.Now().UTC()
-
-	result, err := coll.InsertOne(c.Context(), b)
 	if err != nil {
 		return c.Status(http.StatusInternalServerError).JSON(Response{
 			Message: "Failed to create user",
@@ -66,7 +82,7 @@ func CreateUser(c *fiber.Ctx) error {
 	return c.Status(http.StatusCreated).JSON(Response{
 		Message: "User created successfully",
 		Code:    http.StatusCreated,
-		Data:    result,
+		Data:    fiber.Map{"InsertedID": user.ID},
 	})
 }
 
@@ -76,10 +92,9 @@ func CreateUser(c *fiber.Ctx) error {
 // @Produce        json
 // @Param          id path string true "User ID"
 // @Success        200 {object} Response
+// @Security       BearerAuth
 // @Router         /api/v1/users/{id} [get]
-func GetUserByID(c *fiber.Ctx) error {
-	coll := db.GetDBCollection("users")
-
+func (h *Handler) GetUserByID(c *fiber.Ctx) error {
 	id := c.Params("id")
 	if id == "" {
 		return c.Status(http.StatusBadRequest).JSON(Response{
@@ -95,16 +110,21 @@ func GetUserByID(c *fiber.Ctx) error {
 			Code:    http.StatusBadRequest,
 		})
 	}
+	if objectId != currentUser(c).ID {
+		return c.Status(http.StatusNotFound).JSON(Response{
+			Message: "User not found",
+			Code:    http.StatusNotFound,
+		})
+	}
 
-	user := model.User{}
-
-	err = coll.FindOne(c.Context(), bson.M{"_id": objectId}).Decode(&user)
+	user, err := h.models.Users.FindByID(c.Context(), objectId)
 	if err != nil {
 		return c.Status(http.StatusNotFound).JSON(Response{
 			Message: "User not found",
 			Code:    http.StatusNotFound,
 		})
 	}
+	user.Settings = user.Settings.WithDefaults()
 
 	return c.Status(http.StatusOK).JSON(Response{
 		Message: "User found",
@@ -121,10 +141,9 @@ func GetUserByID(c *fiber.Ctx) error {
 // @Param          id path string true "User ID"
 // @Param          user body model.UpdateUserDTO true "User Data"
 // @Success        200 {object} Response
+// @Security       BearerAuth
 // @Router         /api/v1/users/{id} [put]
-func UpdateUserByID(c *fiber.Ctx) error {
-	coll := db.GetDBCollection("users")
-
+func (h *Handler) UpdateUserByID(c *fiber.Ctx) error {
 	id := c.Params("id")
 	if id == "" {
 		return c.Status(http.StatusBadRequest).JSON(Response{
@@ -157,7 +176,20 @@ func UpdateUserByID(c *fiber.Ctx) error {
 		})
 	}
 
-	_, err = coll.UpdateOne(c.Context(), bson.M{"_id": objectId}, bson.M{"$set": b})
+	if objectId != currentUser(c).ID {
+		return c.Status(http.StatusNotFound).JSON(Response{
+			Message: "User not found",
+			Code:    http.StatusNotFound,
+		})
+	}
+
+	err = h.models.Users.Update(c.Context(), objectId, *b)
+	if errors.Is(err, model.ErrNotFound) {
+		return c.Status(http.StatusNotFound).JSON(Response{
+			Message: "User not found",
+			Code:    http.StatusNotFound,
+		})
+	}
 	if err != nil {
 		return c.Status(http.StatusInternalServerError).JSON(Response{
 			Message: err.Error(),
@@ -170,3 +202,177 @@ func UpdateUserByID(c *fiber.Ctx) error {
 		Code:    http.StatusOK,
 	})
 }
+
+// @Summary        Patch User by ID
+// @Description    Applies an RFC 7396 JSON merge patch to the name and settings of a user and returns the updated user. A null setting resets it to its default
+// @Tags           User
+// @Accept         json
+// @Accept         application/merge-patch+json
+// @Produce        json
+// @Param          id path string true "User ID"
+// @Param          patch body model.UserPatch true "Merge patch"
+// @Success        200 {object} Response{data=model.User}
+// @Security       BearerAuth
+// @Router         /api/v1/users/{id} [patch]
+func (h *Handler) PatchUserByID(c *fiber.Ctx) error {
+	objectId, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: "Invalid ID",
+			Code:    http.StatusBadRequest,
+		})
+	}
+
+	user := currentUser(c)
+	if objectId != user.ID {
+		return c.Status(http.StatusNotFound).JSON(Response{
+			Message: "User not found",
+			Code:    http.StatusNotFound,
+		})
+	}
+
+	patch := user.Editable()
+	if err := mergepatch.ApplyTo(&patch, c.Body()); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+	}
+	patch.Settings = patch.Settings.WithDefaults()
+
+	validate := validator.New()
+	if err := validate.Struct(patch); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+	}
+	if _, err := time.LoadLocation(patch.Settings.Timezone); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: "Invalid time zone",
+			Code:    http.StatusBadRequest,
+		})
+	}
+
+	updated, err := h.models.Users.Patch(c.Context(), user.ID, patch)
+	if errors.Is(err, model.ErrNotFound) {
+		return c.Status(http.StatusNotFound).JSON(Response{
+			Message: "User not found",
+			Code:    http.StatusNotFound,
+		})
+	}
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(Response{
+			Message: "Failed to update user",
+			Code:    http.StatusInternalServerError,
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(Response{
+		Message: "User updated successfully",
+		Code:    http.StatusOK,
+		Data:    updated,
+	})
+}
+
+// @Summary        Get User Settings
+// @Description    Retrieves the pomodoro settings of a user
+// @Tags           User
+// @Produce        json
+// @Param          id path string true "User ID"
+// @Success        200 {object} Response{data=model.Settings}
+// @Security       BearerAuth
+// @Router         /api/v1/users/{id}/settings [get]
+func (h *Handler) GetUserSettings(c *fiber.Ctx) error {
+	objectId, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: "Invalid ID",
+			Code:    http.StatusBadRequest,
+		})
+	}
+
+	user := currentUser(c)
+	if objectId != user.ID {
+		return c.Status(http.StatusNotFound).JSON(Response{
+			Message: "User not found",
+			Code:    http.StatusNotFound,
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(Response{
+		Message: "Settings found",
+		Code:    http.StatusOK,
+		Data:    user.Settings.WithDefaults(),
+	})
+}
+
+// @Summary        Update User Settings
+// @Description    Updates the pomodoro settings of a user, fields left out keep their current value
+// @Tags           User
+// @Accept         json
+// @Produce        json
+// @Param          id path string true "User ID"
+// @Param          settings body model.Settings true "Settings Data"
+// @Success        200 {object} Response{data=model.Settings}
+// @Security       BearerAuth
+// @Router         /api/v1/users/{id}/settings [put]
+func (h *Handler) UpdateUserSettings(c *fiber.Ctx) error {
+	objectId, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: "Invalid ID",
+			Code:    http.StatusBadRequest,
+		})
+	}
+
+	user := currentUser(c)
+	if objectId != user.ID {
+		return c.Status(http.StatusNotFound).JSON(Response{
+			Message: "User not found",
+			Code:    http.StatusNotFound,
+		})
+	}
+
+	settings := user.Settings.WithDefaults()
+	if err := c.BodyParser(&settings); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(settings); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+	}
+	if _, err := time.LoadLocation(settings.Timezone); err != nil || settings.Timezone == "" {
+		return c.Status(http.StatusBadRequest).JSON(Response{
+			Message: "Invalid time zone",
+			Code:    http.StatusBadRequest,
+		})
+	}
+
+	err = h.models.Users.UpdateSettings(c.Context(), user.ID, settings)
+	if errors.Is(err, model.ErrNotFound) {
+		return c.Status(http.StatusNotFound).JSON(Response{
+			Message: "User not found",
+			Code:    http.StatusNotFound,
+		})
+	}
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(Response{
+			Message: "Failed to update settings",
+			Code:    http.StatusInternalServerError,
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(Response{
+		Message: "Settings updated successfully",
+		Code:    http.StatusOK,
+		Data:    settings,
+	})
+}
@@ -0,0 +1,102 @@
+package handler_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/anggara-26/pomodoro-backend.git/app/model"
+)
+
+func TestUserOwnership(t *testing.T) {
+	s := newTestServer(t)
+	owner := s.signUp(t, "owner")
+	other := s.signUp(t, "other")
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   any
+	}{
+		{"get", "GET", "/api/v1/users/" + owner.ID, nil},
+		{"update", "PUT", "/api/v1/users/" + owner.ID, map[string]any{"name": "Mallory"}},
+		{"patch", "PATCH", "/api/v1/users/" + owner.ID, map[string]any{"name": "Mallory"}},
+		{"get settings", "GET", "/api/v1/users/" + owner.ID + "/settings", nil},
+		{"update settings", "PUT", "/api/v1/users/" + owner.ID + "/settings", map[string]any{"focus_minutes": 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := s.do(t, other, tt.method, tt.path, tt.body)
+			if res.Status != http.StatusNotFound {
+				t.Errorf("status = %d (%s), want %d", res.Status, res.Message, http.StatusNotFound)
+			}
+		})
+	}
+
+	var user model.User
+	s.do(t, owner, "GET", "/api/v1/users/"+owner.ID, nil).decode(t, &user)
+	if user.Name != "owner" || user.Settings.FocusMinutes != model.DefaultSettings.FocusMinutes {
+		t.Errorf("user = %+v, want it untouched", user)
+	}
+}
+
+func TestSignUpTwice(t *testing.T) {
+	s := newTestServer(t)
+	user := s.signUp(t, "user")
+
+	res := s.do(t, user, "POST", "/api/v1/users", map[string]string{"email": "user@example.com", "name": "Again"})
+	if res.Status != http.StatusBadRequest {
+		t.Errorf("status = %d (%s), want %d", res.Status, res.Message, http.StatusBadRequest)
+	}
+}
+
+func TestPatchUser(t *testing.T) {
+	tests := []struct {
+		name  string
+		patch map[string]any
+		want  int
+		check func(t *testing.T, user model.User)
+	}{
+		{
+			name:  "rename",
+			patch: map[string]any{"name": "Renamed"},
+			want:  http.StatusOK,
+			check: func(t *testing.T, user model.User) {
+				if user.Name != "Renamed" {
+					t.Errorf("name = %q, want Renamed", user.Name)
+				}
+			},
+		},
+		{
+			name:  "null setting resets it",
+			patch: map[string]any{"settings": map[string]any{"focus_minutes": nil, "timezone": "Asia/Jakarta"}},
+			want:  http.StatusOK,
+			check: func(t *testing.T, user model.User) {
+				if user.Settings.FocusMinutes != model.DefaultSettings.FocusMinutes || user.Settings.Timezone != "Asia/Jakarta" {
+					t.Errorf("settings = %+v, want the default focus length in Asia/Jakarta", user.Settings)
+				}
+			},
+		},
+		{name: "null name", patch: map[string]any{"name": nil}, want: http.StatusBadRequest},
+		{name: "unknown field", patch: map[string]any{"email": "new@example.com"}, want: http.StatusBadRequest},
+		{name: "invalid time zone", patch: map[string]any{"settings": map[string]any{"timezone": "Mars/Base"}}, want: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t)
+			user := s.signUp(t, "user")
+
+			res := s.do(t, user, "PATCH", "/api/v1/users/"+user.ID, tt.patch)
+			if res.Status != tt.want {
+				t.Fatalf("status = %d (%s), want %d", res.Status, res.Message, tt.want)
+			}
+			if tt.check != nil {
+				var updated model.User
+				res.decode(t, &updated)
+				tt.check(t, updated)
+			}
+		})
+	}
+}
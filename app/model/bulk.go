@@ -0,0 +1,52 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type BulkOperation string
+
+const (
+	BulkSetStatus  BulkOperation = "set_status"
+	BulkDelete     BulkOperation = "delete"
+	BulkRestore    BulkOperation = "restore"
+	BulkSetTags    BulkOperation = "set_tags"
+	BulkReschedule BulkOperation = "reschedule"
+)
+
+// BulkTaskDTO applies one operation to up to 100 tasks. Status, Tags and
+// AssignedAt are the argument of set_status, set_tags and reschedule.
+type BulkTaskDTO struct {
+	IDs        []primitive.ObjectID `json:"ids" validate:"required,min=1,max=100,unique"`
+	Operation  BulkOperation        `json:"operation" validate:"required,oneof=set_status delete restore set_tags reschedule"`
+	Status     TaskStatus           `json:"status,omitempty" validate:"omitempty,oneof=pending in_progress completed"`
+	Tags       []string             `json:"tags,omitempty" validate:"max=20,dive,max=32"`
+	AssignedAt *time.Time           `json:"assigned_at,omitempty"`
+}
+
+// BulkResult is the outcome of a bulk operation for one task.
+type BulkResult struct {
+	ID    primitive.ObjectID `json:"id"`
+	OK    bool               `json:"ok"`
+	Error string             `json:"error,omitempty"`
+}
+
+// Check returns why the operation cannot be applied to a task in status
+// current: ErrNotFound for a trashed task unless restoring it and the other
+// way around, ErrInvalidTransition for an illegal status change.
+func (op BulkTaskDTO) Check(current TaskStatus) error {
+	switch {
+	case op.Operation == BulkRestore:
+		if current != TaskDeleted {
+			return ErrNotFound
+		}
+	case current == TaskDeleted:
+		return ErrNotFound
+	case op.Operation == BulkSetStatus && !current.CanTransitionTo(op.Status):
+		return ErrInvalidTransition
+	}
+
+	return nil
+}
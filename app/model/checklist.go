@@ -0,0 +1,62 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MaxChecklistItems is the number of checklist items a task may hold.
+const MaxChecklistItems = 100
+
+// ChecklistItem is a step of a task, kept in the order the user arranged.
+type ChecklistItem struct {
+	ID                 primitive.ObjectID `json:"id" bson:"_id"`
+	Title              string             `json:"title" bson:"title"`
+	Done               bool               `json:"done" bson:"done"`
+	DoneAt             *time.Time         `json:"done_at,omitempty" bson:"done_at,omitempty"`
+	EstimatedPomodoros int16              `json:"estimated_pomodoros,omitempty" bson:"estimated_pomodoros,omitempty"`
+}
+
+type AddChecklistItemDTO struct {
+	Title              string `json:"title" validate:"required,max=200"`
+	EstimatedPomodoros int16  `json:"estimated_pomodoros,omitempty" validate:"min=0,max=100"`
+}
+
+type ReorderChecklistDTO struct {
+	// ItemIDs lists every item of the checklist in the new order.
+	ItemIDs []primitive.ObjectID `json:"item_ids" validate:"required"`
+}
+
+// RollupEstimate sets EstimatedPomodoros to the sum of the checklist item
+// estimates. Tasks without estimated items keep their own estimate, for the
+// others the sum wins over any estimate set on the task itself.
+func (t *Task) RollupEstimate() {
+	var sum int16
+	for _, item := range t.Checklist {
+		sum += item.EstimatedPomodoros
+	}
+	if sum > 0 {
+		t.EstimatedPomodoros = sum
+	}
+}
+
+// IsChecklistOrder reports whether ids is a reordering of the checklist items.
+func (t Task) IsChecklistOrder(ids []primitive.ObjectID) bool {
+	if len(ids) != len(t.Checklist) {
+		return false
+	}
+
+	seen := map[primitive.ObjectID]bool{}
+	for _, item := range t.Checklist {
+		seen[item.ID] = true
+	}
+	for _, id := range ids {
+		if !seen[id] {
+			return false
+		}
+		delete(seen, id)
+	}
+
+	return true
+}
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrInvalidCursor is returned when a cursor token cannot be decoded.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// TaskCursor is the position of a task in task listings, which are ordered by
+// created_at and then _id, both descending.
+type TaskCursor struct {
+	CreatedAt time.Time          `json:"t"`
+	ID        primitive.ObjectID `json:"id"`
+}
+
+func NewTaskCursor(task Task) TaskCursor {
+	return TaskCursor{CreatedAt: task.CreatedAt, ID: task.ID}
+}
+
+// String encodes the cursor as an opaque URL-safe token.
+func (c TaskCursor) String() string {
+	data, _ := json.Marshal(c)
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func ParseTaskCursor(token string) (TaskCursor, error) {
+	var c TaskCursor
+
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return c, ErrInvalidCursor
+	}
+	if err := json.Unmarshal(data, &c); err != nil || c.ID.IsZero() {
+		return c, ErrInvalidCursor
+	}
+
+	return c, nil
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestTaskCursor(t *testing.T) {
+	task := Task{ID: primitive.NewObjectID(), CreatedAt: time.Date(2026, 3, 1, 9, 30, 0, 123456789, time.UTC)}
+
+	got, err := ParseTaskCursor(NewTaskCursor(task).String())
+	if err != nil {
+		t.Fatalf("ParseTaskCursor() error = %v", err)
+	}
+	if got.ID != task.ID || !got.CreatedAt.Equal(task.CreatedAt) {
+		t.Errorf("ParseTaskCursor() = %+v, want the cursor of %+v", got, task)
+	}
+}
+
+func TestParseTaskCursorInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"empty", ""},
+		{"not base64", "not a cursor!"},
+		{"not json", base64.RawURLEncoding.EncodeToString([]byte("cursor"))},
+		{"missing id", base64.RawURLEncoding.EncodeToString([]byte(`{"t":"2026-03-01T09:30:00Z"}`))},
+		{"invalid id", base64.RawURLEncoding.EncodeToString([]byte(`{"t":"2026-03-01T09:30:00Z","id":"nope"}`))},
+		{"padded", base64.URLEncoding.EncodeToString([]byte(`{"id":"`+primitive.NewObjectID().Hex()+`"}`)) + "="},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseTaskCursor(tt.token); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("ParseTaskCursor(%q) error = %v, want ErrInvalidCursor", tt.token, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package model
+
+import "time"
+
+// CycleRules drive the focus, short break and long break cycle.
+type CycleRules struct {
+	FocusMinutes      int16
+	ShortBreakMinutes int16
+	LongBreakMinutes  int16
+	// LongBreakInterval is the number of focus sessions before a long break.
+	LongBreakInterval int
+	// IdleReset starts a new cycle when no session ended for this long.
+	IdleReset time.Duration
+}
+
+// Duration returns the length of a session of type t in minutes.
+func (r CycleRules) Duration(t SessionType) int16 {
+	switch t {
+	case ShortBreak:
+		return r.ShortBreakMinutes
+	case LongBreak:
+		return r.LongBreakMinutes
+	default:
+		return r.FocusMinutes
+	}
+}
+
+// Next picks the type of the session that follows history, which holds the
+// ended sessions of a user, most recent first. A break is followed by focus, and
+// focus by a break that is long after every LongBreakInterval completed focus
+// sessions. An idle gap longer than IdleReset starts the cycle over.
+func (r CycleRules) Next(history []Session, now time.Time) SessionType {
+	if len(history) == 0 || now.Sub(history[0].EndedAt) > r.IdleReset || history[0].Type != Focus {
+		return Focus
+	}
+
+	focused := 0
+	for i, session := range history {
+		if session.Type == LongBreak {
+			break
+		}
+		if i > 0 && history[i-1].StartedAt.Sub(session.EndedAt) > r.IdleReset {
+			break
+		}
+		if session.Type == Focus && session.Status == SessionCompleted {
+			focused++
+		}
+	}
+
+	if focused > 0 && focused%r.LongBreakInterval == 0 {
+		return LongBreak
+	}
+
+	return ShortBreak
+}
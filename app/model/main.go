@@ -1,7 +1,9 @@
 package model
 
+// Models groups the repositories the handlers depend on.
 type Models struct {
-	Task    Task
-	User    User
-	Session Session
+	Tasks    TaskRepository
+	Users    UserRepository
+	Sessions SessionRepository
+	Projects ProjectRepository
 }
@@ -0,0 +1,68 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TaskPatch holds the fields of a task a merge patch may change. Nil
+// fields are removed from the task.
+type TaskPatch struct {
+	ProjectID          *primitive.ObjectID `json:"project_id" bson:"project_id"`
+	Title              string              `json:"title" bson:"title" validate:"required"`
+	Description        *string             `json:"description" bson:"description"`
+	AssignedAt         time.Time           `json:"assigned_at" bson:"assigned_at"`
+	DueAt              *time.Time          `json:"due_at" bson:"due_at"`
+	Priority           Priority            `json:"priority" bson:"priority" validate:"min=0,max=3"`
+	Status             TaskStatus          `json:"status" bson:"status" validate:"oneof=pending in_progress completed"`
+	EstimatedPomodoros int16               `json:"estimated_pomodoros" bson:"estimated_pomodoros" validate:"min=1"`
+	Tags               []string            `json:"tags" bson:"tags" validate:"max=20,dive,max=32"`
+	Recurrence         *Recurrence         `json:"recurrence" bson:"recurrence"`
+}
+
+// Editable returns the fields of the task a merge patch applies to.
+func (t Task) Editable() TaskPatch {
+	return TaskPatch{
+		ProjectID:          t.ProjectID,
+		Title:              t.Title,
+		Description:        t.Description,
+		AssignedAt:         t.AssignedAt,
+		DueAt:              t.DueAt,
+		Priority:           t.Priority,
+		Status:             t.Status,
+		EstimatedPomodoros: t.EstimatedPomodoros,
+		Tags:               t.Tags,
+		Recurrence:         t.Recurrence,
+	}
+}
+
+// ApplyPatch replaces the editable fields of the task with p.
+func (t *Task) ApplyPatch(p TaskPatch, at time.Time) {
+	t.ProjectID = p.ProjectID
+	t.Title = p.Title
+	t.Description = p.Description
+	t.AssignedAt = p.AssignedAt
+	t.DueAt = p.DueAt
+	t.Priority = p.Priority
+	t.SetStatus(p.Status, at)
+	t.EstimatedPomodoros = p.EstimatedPomodoros
+	t.Tags = p.Tags
+	t.Recurrence = p.Recurrence
+	t.UpdatedAt = at
+	t.RollupEstimate()
+}
+
+// UserPatch holds the fields of a user a merge patch may change.
+type UserPatch struct {
+	Name     string   `json:"name" bson:"name" validate:"required"`
+	Settings Settings `json:"settings" bson:"settings"`
+}
+
+// Editable returns the fields of the user a merge patch applies to.
+func (u User) Editable() UserPatch {
+	return UserPatch{
+		Name:     u.Name,
+		Settings: u.Settings.WithDefaults(),
+	}
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PositionGap is the distance between renumbered tasks, leaving room for
+// moves between them.
+const PositionGap int64 = 1 << 16
+
+// InitialPosition places a new task after all existing ones. Creation time in
+// microseconds keeps increasing and stays above renumbered positions.
+func InitialPosition(createdAt time.Time) int64 {
+	return createdAt.UnixMicro()
+}
+
+// PositionBetween returns a position between prev and next, either of which
+// is nil at an end of the list. It reports false if there is no room left.
+func PositionBetween(prev, next *int64) (int64, bool) {
+	switch {
+	case prev == nil && next == nil:
+		return PositionGap, true
+	case prev == nil:
+		return *next - PositionGap, true
+	case next == nil:
+		return *prev + PositionGap, true
+	case *next-*prev < 2:
+		return 0, false
+	default:
+		return *prev + (*next-*prev)/2, true
+	}
+}
+
+// MoveTaskDTO places a task right before BeforeID, right after AfterID, or
+// between the two.
+type MoveTaskDTO struct {
+	BeforeID *primitive.ObjectID `json:"before_id,omitempty"`
+	AfterID  *primitive.ObjectID `json:"after_id,omitempty"`
+}
@@ -0,0 +1,34 @@
+package model
+
+import "testing"
+
+func TestPositionBetween(t *testing.T) {
+	at := func(p int64) *int64 { return &p }
+
+	tests := []struct {
+		name   string
+		prev   *int64
+		next   *int64
+		want   int64
+		wantOK bool
+	}{
+		{"empty list", nil, nil, PositionGap, true},
+		{"first", nil, at(100), 100 - PositionGap, true},
+		{"last", at(100), nil, 100 + PositionGap, true},
+		{"middle", at(100), at(200), 150, true},
+		{"odd gap", at(100), at(103), 101, true},
+		{"gap of two", at(100), at(102), 101, true},
+		{"adjacent", at(100), at(101), 0, false},
+		{"tie", at(100), at(100), 0, false},
+		{"negative", at(-300), at(-100), -200, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := PositionBetween(tt.prev, tt.next)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("PositionBetween() = %d, %v, want %d, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Project groups the tasks of a user, e.g. by client.
+type Project struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id"`
+	UserID      primitive.ObjectID `json:"user_id" bson:"user_id"`
+	Name        string             `json:"name" bson:"name"`
+	Description *string            `json:"description,omitempty" bson:"description,omitempty"`
+	CreatedAt   time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+type CreateProjectDTO struct {
+	Name        string  `json:"name" validate:"required,max=100"`
+	Description *string `json:"description,omitempty"`
+}
+
+type UpdateProjectDTO struct {
+	Name        *string   `json:"name,omitempty" bson:"name,omitempty" validate:"omitempty,min=1,max=100"`
+	Description *string   `json:"description,omitempty" bson:"description,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+}
+
+// ProjectRollup sums up the non-deleted tasks of a project and the focus
+// sessions spent on any of its tasks.
+type ProjectRollup struct {
+	ProjectID          primitive.ObjectID `json:"project_id" bson:"-"`
+	Tasks              int64              `json:"tasks" bson:"tasks"`
+	CompletedTasks     int64              `json:"completed_tasks" bson:"completed_tasks"`
+	EstimatedPomodoros int64              `json:"estimated_pomodoros" bson:"estimated_pomodoros"`
+	CompletedPomodoros int64              `json:"completed_pomodoros" bson:"completed_pomodoros"`
+	FocusMinutes       float64            `json:"focus_minutes" bson:"focus_minutes"`
+}
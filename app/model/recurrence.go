@@ -0,0 +1,140 @@
+package model
+
+import (
+	"slices"
+	"time"
+)
+
+type RecurrenceFrequency string
+
+const (
+	RecurDaily    RecurrenceFrequency = "daily"
+	RecurWeekdays RecurrenceFrequency = "weekdays"
+	RecurWeekly   RecurrenceFrequency = "weekly"
+	RecurMonthly  RecurrenceFrequency = "monthly"
+)
+
+// Recurrence repeats a task on a schedule. Occurrences keep the time of day of
+// the task they follow.
+type Recurrence struct {
+	Frequency RecurrenceFrequency `json:"frequency" bson:"frequency" validate:"required,oneof=daily weekdays weekly monthly"`
+	// Weekdays are the days a weekly task repeats on, 0 being Sunday.
+	Weekdays []time.Weekday `json:"weekdays,omitempty" bson:"weekdays,omitempty" validate:"max=7,dive,min=0,max=6" swaggertype:"array,integer"`
+	// DayOfMonth is the day a monthly task repeats on, moved to the last day
+	// of shorter months.
+	DayOfMonth int `json:"day_of_month,omitempty" bson:"day_of_month,omitempty" validate:"min=0,max=31"`
+}
+
+// Anchor fills the days left unset from the date of the first occurrence.
+func (r *Recurrence) Anchor(first time.Time) {
+	if r.Frequency == RecurWeekly && len(r.Weekdays) == 0 {
+		r.Weekdays = []time.Weekday{first.Weekday()}
+	}
+	if r.Frequency == RecurMonthly && r.DayOfMonth == 0 {
+		r.DayOfMonth = first.Day()
+	}
+}
+
+// Next returns the occurrence following the one at t, with days counted in loc.
+func (r Recurrence) Next(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+
+	switch r.Frequency {
+	case RecurWeekdays:
+		for {
+			t = t.AddDate(0, 0, 1)
+			if t.Weekday() != time.Saturday && t.Weekday() != time.Sunday {
+				return t
+			}
+		}
+	case RecurWeekly:
+		weekdays := r.Weekdays
+		if len(weekdays) == 0 {
+			weekdays = []time.Weekday{t.Weekday()}
+		}
+		for i := 1; i < 7; i++ {
+			if next := t.AddDate(0, 0, i); slices.Contains(weekdays, next.Weekday()) {
+				return next
+			}
+		}
+		return t.AddDate(0, 0, 7)
+	case RecurMonthly:
+		day := r.DayOfMonth
+		if day == 0 {
+			day = t.Day()
+		}
+		year, month, _ := t.Date()
+		lastDay := time.Date(year, month+2, 0, 0, 0, 0, 0, loc).Day()
+		return time.Date(year, month+1, min(day, lastDay), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+	default:
+		return t.AddDate(0, 0, 1)
+	}
+}
+
+// NextOccurrence returns the task that follows t in its series, assigned to
+// the first occurrence that is not on a day before today. It reports false if
+// t does not recur.
+func (t Task) NextOccurrence(now time.Time, loc *time.Location) (Task, bool) {
+	if t.Recurrence == nil {
+		return Task{}, false
+	}
+
+	y, m, d := now.In(loc).Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, loc)
+
+	assignedAt := t.Recurrence.Next(t.AssignedAt, loc)
+	for assignedAt.Before(today) {
+		assignedAt = t.Recurrence.Next(assignedAt, loc)
+	}
+
+	seriesID := t.ID
+	if t.SeriesID != nil {
+		seriesID = *t.SeriesID
+	}
+
+	// The due date keeps its distance from the assigned date.
+	var dueAt *time.Time
+	if t.DueAt != nil {
+		due := assignedAt.Add(t.DueAt.Sub(t.AssignedAt)).UTC()
+		dueAt = &due
+	}
+
+	checklist := make([]ChecklistItem, len(t.Checklist))
+	for i, item := range t.Checklist {
+		item.Done, item.DoneAt = false, nil
+		checklist[i] = item
+	}
+
+	return Task{
+		UserID:             t.UserID,
+		ProjectID:          t.ProjectID,
+		SeriesID:           &seriesID,
+		Title:              t.Title,
+		Description:        t.Description,
+		AssignedAt:         assignedAt.UTC(),
+		DueAt:              dueAt,
+		Priority:           t.Priority,
+		Position:           InitialPosition(now),
+		Status:             TaskPending,
+		EstimatedPomodoros: t.EstimatedPomodoros,
+		Tags:               t.Tags,
+		Checklist:          checklist,
+		Recurrence:         t.Recurrence,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}, true
+}
+
+// IsDue reports whether the next occurrence of a recurring task should be
+// created, which is once it is completed or its day has passed in loc.
+func (t Task) IsDue(now time.Time, loc *time.Location) bool {
+	if t.Recurrence == nil || t.NextCreated || t.Status == TaskDeleted {
+		return false
+	}
+	if t.Status == TaskCompleted {
+		return true
+	}
+
+	y, m, d := t.AssignedAt.In(loc).Date()
+	return !now.Before(time.Date(y, m, d+1, 0, 0, 0, 0, loc))
+}
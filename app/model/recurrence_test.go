@@ -0,0 +1,88 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecurrenceNext(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		recurrence Recurrence
+		at         time.Time
+		loc        *time.Location
+		want       time.Time
+	}{
+		{"daily", Recurrence{Frequency: RecurDaily}, time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC), time.UTC, time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)},
+		{"weekdays skip the weekend", Recurrence{Frequency: RecurWeekdays}, time.Date(2026, 3, 6, 9, 0, 0, 0, time.UTC), time.UTC, time.Date(2026, 3, 9, 9, 0, 0, 0, time.UTC)},
+		{"weekdays from a saturday", Recurrence{Frequency: RecurWeekdays}, time.Date(2026, 3, 7, 9, 0, 0, 0, time.UTC), time.UTC, time.Date(2026, 3, 9, 9, 0, 0, 0, time.UTC)},
+		{"weekly to a later weekday", Recurrence{Frequency: RecurWeekly, Weekdays: []time.Weekday{time.Monday, time.Thursday}}, time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC), time.UTC, time.Date(2026, 3, 5, 9, 0, 0, 0, time.UTC)},
+		{"weekly into the next week", Recurrence{Frequency: RecurWeekly, Weekdays: []time.Weekday{time.Monday, time.Thursday}}, time.Date(2026, 3, 5, 9, 0, 0, 0, time.UTC), time.UTC, time.Date(2026, 3, 9, 9, 0, 0, 0, time.UTC)},
+		{"weekly without weekdays", Recurrence{Frequency: RecurWeekly}, time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC), time.UTC, time.Date(2026, 3, 9, 9, 0, 0, 0, time.UTC)},
+		{"monthly clamps to the month end", Recurrence{Frequency: RecurMonthly, DayOfMonth: 31}, time.Date(2026, 1, 31, 9, 0, 0, 0, time.UTC), time.UTC, time.Date(2026, 2, 28, 9, 0, 0, 0, time.UTC)},
+		{"monthly returns to its day", Recurrence{Frequency: RecurMonthly, DayOfMonth: 31}, time.Date(2026, 2, 28, 9, 0, 0, 0, time.UTC), time.UTC, time.Date(2026, 3, 31, 9, 0, 0, 0, time.UTC)},
+		{"monthly in a leap year", Recurrence{Frequency: RecurMonthly, DayOfMonth: 30}, time.Date(2028, 1, 30, 9, 0, 0, 0, time.UTC), time.UTC, time.Date(2028, 2, 29, 9, 0, 0, 0, time.UTC)},
+		{"monthly without a day", Recurrence{Frequency: RecurMonthly}, time.Date(2026, 3, 15, 9, 0, 0, 0, time.UTC), time.UTC, time.Date(2026, 4, 15, 9, 0, 0, 0, time.UTC)},
+		// Friday evening in UTC is already Saturday in Jakarta.
+		{"weekdays in the user time zone", Recurrence{Frequency: RecurWeekdays}, time.Date(2026, 3, 6, 20, 0, 0, 0, time.UTC), jakarta, time.Date(2026, 3, 8, 20, 0, 0, 0, time.UTC)},
+		{"daily keeps the local time over dst", Recurrence{Frequency: RecurDaily}, time.Date(2026, 3, 7, 9, 0, 0, 0, newYork), newYork, time.Date(2026, 3, 8, 9, 0, 0, 0, newYork)},
+	}
+
+	for _, tt := range tests {
+		if got := tt.recurrence.Next(tt.at, tt.loc); !got.Equal(tt.want) {
+			t.Errorf("%s: Next(%s) = %s, want %s", tt.name, tt.at, got, tt.want)
+		}
+	}
+}
+
+func TestTaskIsDue(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	daily := &Recurrence{Frequency: RecurDaily}
+	today := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	yesterday := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		task Task
+		now  time.Time
+		loc  *time.Location
+		want bool
+	}{
+		{"not recurring", Task{AssignedAt: yesterday, Status: TaskPending}, now, time.UTC, false},
+		{"next already created", Task{AssignedAt: yesterday, Status: TaskPending, Recurrence: daily, NextCreated: true}, now, time.UTC, false},
+		{"trashed", Task{AssignedAt: yesterday, Status: TaskDeleted, Recurrence: daily}, now, time.UTC, false},
+		{"completed today", Task{AssignedAt: today, Status: TaskCompleted, Recurrence: daily}, now, time.UTC, true},
+		{"pending today", Task{AssignedAt: today, Status: TaskPending, Recurrence: daily}, now, time.UTC, false},
+		{"pending yesterday", Task{AssignedAt: yesterday, Status: TaskPending, Recurrence: daily}, now, time.UTC, true},
+		// Assigned on March 1 in UTC but March 2 in Jakarta.
+		{"day passed in utc", Task{AssignedAt: time.Date(2026, 3, 1, 20, 0, 0, 0, time.UTC), Status: TaskPending, Recurrence: daily}, now, time.UTC, true},
+		{"day not passed in jakarta", Task{AssignedAt: time.Date(2026, 3, 1, 20, 0, 0, 0, time.UTC), Status: TaskPending, Recurrence: daily}, now, jakarta, false},
+		// Assigned at 22:00 on March 1 in New York, due at its midnight.
+		{"before local midnight", Task{AssignedAt: time.Date(2026, 3, 2, 3, 0, 0, 0, time.UTC), Status: TaskPending, Recurrence: daily}, time.Date(2026, 3, 2, 4, 59, 0, 0, time.UTC), newYork, false},
+		{"at local midnight", Task{AssignedAt: time.Date(2026, 3, 2, 3, 0, 0, 0, time.UTC), Status: TaskPending, Recurrence: daily}, time.Date(2026, 3, 2, 5, 0, 0, 0, time.UTC), newYork, true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.task.IsDue(tt.now, tt.loc); got != tt.want {
+			t.Errorf("%s: IsDue(%s) = %v, want %v", tt.name, tt.now, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	// ErrNotFound is returned by repositories when no document matches.
+	ErrNotFound = errors.New("not found")
+	// ErrConflict is returned when a write lost a race against a concurrent one.
+	ErrConflict = errors.New("conflict")
+	// ErrInvalidTransition is returned when a task cannot move to the requested status.
+	ErrInvalidTransition = errors.New("invalid status transition")
+)
+
+type TaskFilter struct {
+	UserID primitive.ObjectID
+	Status TaskStatus
+	// Title matches tasks whose title starts with it, ignoring case.
+	Title string
+	// Query is a full-text search over title and description. Matches are
+	// ranked by relevance instead of the creation order.
+	Query string
+	// Tags matches tasks carrying any of the tags, or all of them with TagsMatchAll.
+	Tags         []string
+	TagsMatchAll bool
+	ProjectID    *primitive.ObjectID
+	Priority     *Priority
+	DueAfter     *time.Time
+	DueBefore    *time.Time
+	// SortBy is one of "created_at", "assigned_at", "due_at", "priority",
+	// "position" or "deleted_at",
+	// ties are broken by the creation order. Cursor pagination only supports
+	// "created_at".
+	SortBy    string
+	SortDesc  bool
+	StartDate *time.Time
+	EndDate   *time.Time
+	Skip      int64
+	Limit     int64
+}
+
+type TaskRepository interface {
+	Create(ctx context.Context, task *Task) error
+	FindByID(ctx context.Context, userID, id primitive.ObjectID) (Task, error)
+	// Update returns ErrNotFound for trashed tasks and ErrInvalidTransition
+	// when update.Status cannot be reached from the current status of the task.
+	Update(ctx context.Context, userID, id primitive.ObjectID, update UpdateTaskDTO) error
+	// Patch replaces the editable fields of a task that is not in the trash
+	// and returns the result. It returns ErrConflict when the task was updated
+	// since unmodifiedSince and ErrInvalidTransition for an illegal status.
+	Patch(ctx context.Context, userID, id primitive.ObjectID, patch TaskPatch, unmodifiedSince, at time.Time) (Task, error)
+	// Delete moves a task to the trash. It returns ErrNotFound if the task is already trashed.
+	Delete(ctx context.Context, userID, id primitive.ObjectID, deletedAt time.Time) error
+	// Restore takes a task out of the trash with the status it had before.
+	Restore(ctx context.Context, userID, id primitive.ObjectID, at time.Time) (Task, error)
+	// Purge removes up to limit tasks trashed before deletedBefore for good,
+	// longest trashed first, along with their sessions. It returns the number
+	// of tasks removed.
+	Purge(ctx context.Context, deletedBefore time.Time, limit int64) (int64, error)
+	// Bulk applies op to the tasks of userID in one write and reports the
+	// outcome per ID, in the order of op.IDs, see BulkTaskDTO.Check. It
+	// returns ErrConflict, having changed nothing, when the tasks changed
+	// under the write.
+	Bulk(ctx context.Context, userID primitive.ObjectID, op BulkTaskDTO, at time.Time) ([]BulkResult, error)
+	// List returns one page of tasks matching filter, newest first unless
+	// filter.SortBy says otherwise, and the total number of matches.
+	List(ctx context.Context, filter TaskFilter) ([]Task, int64, error)
+	// ListAfter returns up to filter.Limit tasks following after in listing
+	// order, or the first ones if after is nil. Skip is ignored and no total is
+	// counted.
+	ListAfter(ctx context.Context, filter TaskFilter, after *TaskCursor) ([]Task, error)
+	// Tags returns the tags on the user's tasks with their usage, most used first.
+	Tags(ctx context.Context, userID primitive.ObjectID) ([]TagCount, error)
+	// RenameTag replaces from with to on every task of the user, merging the
+	// two where a task carries both. It returns the number of tasks changed.
+	RenameTag(ctx context.Context, userID primitive.ObjectID, from, to string, at time.Time) (int64, error)
+	// The checklist methods return the updated task, with its estimate rolled
+	// up from the items as done by Task.RollupEstimate.
+	AddChecklistItem(ctx context.Context, userID, id primitive.ObjectID, item ChecklistItem, at time.Time) (Task, error)
+	// ReorderChecklist returns ErrConflict if itemIDs no longer match the items of the task.
+	ReorderChecklist(ctx context.Context, userID, id primitive.ObjectID, itemIDs []primitive.ObjectID, at time.Time) (Task, error)
+	SetChecklistItemDone(ctx context.Context, userID, id, itemID primitive.ObjectID, done bool, at time.Time) (Task, error)
+	// Move sets the position of a task to place it right before beforeID,
+	// right after afterID, or between them, renumbering the tasks of the user
+	// when there is no room left. Deleted tasks are not taken into account.
+	Move(ctx context.Context, userID, id primitive.ObjectID, move MoveTaskDTO, at time.Time) (Task, error)
+	// ListForDay returns the non-deleted tasks of the user assigned to or due
+	// in [start, end), by priority and then due date.
+	ListForDay(ctx context.Context, userID primitive.ObjectID, start, end time.Time) ([]Task, error)
+	// ListRecurring returns up to limit recurring tasks of every user whose
+	// next occurrence has not been created yet, and that are completed or were
+	// assigned before the given time. They are ordered by AssignedAt and then
+	// ID, starting after the task after unless it is nil.
+	ListRecurring(ctx context.Context, assignedBefore time.Time, after *Task, limit int64) ([]Task, error)
+	// CreateNextOccurrence inserts next unless its series already has a task
+	// at the same AssignedAt, and marks prev as having its next occurrence.
+	CreateNextOccurrence(ctx context.Context, prev Task, next *Task) error
+}
+
+type SessionFilter struct {
+	UserID    primitive.ObjectID
+	Type      SessionType
+	Status    SessionStatus
+	TaskID    *primitive.ObjectID
+	StartDate *time.Time
+	EndDate   *time.Time
+	// SortBy is one of "started_at", "ended_at" or "duration".
+	SortBy   string
+	SortDesc bool
+	Skip     int64
+	Limit    int64
+}
+
+type StatsQuery struct {
+	UserID   primitive.ObjectID
+	Period   StatsPeriod
+	Location *time.Location
+	Start    time.Time
+	End      time.Time
+}
+
+type SessionRepository interface {
+	// Start moves every active or paused session of the user to SessionBreak
+	// and inserts session, as one atomic step, returning the sessions it moved.
+	// A user never has more than one active session; ErrConflict is returned
+	// if a concurrent start won.
+	Start(ctx context.Context, session *Session) ([]Session, error)
+	FindByID(ctx context.Context, userID, id primitive.ObjectID) (Session, error)
+	// List returns one page of sessions matching filter and the total number of matches.
+	List(ctx context.Context, filter SessionFilter) ([]Session, int64, error)
+	// FindCurrent returns the running session of the user, preferring an active one over a paused one.
+	FindCurrent(ctx context.Context, userID primitive.ObjectID) (Session, error)
+	// ListEnded returns up to limit completed or skipped sessions of the user, most recent first.
+	ListEnded(ctx context.Context, userID primitive.ObjectID, limit int64) ([]Session, error)
+	// ListActive returns the active sessions of every user.
+	ListActive(ctx context.Context) ([]Session, error)
+	// Pause stops the timer of an active session and returns the updated session.
+	Pause(ctx context.Context, userID, id primitive.ObjectID, at time.Time) (Session, error)
+	// Resume restarts the timer of a paused session and returns the updated
+	// session. It returns ErrConflict if another session is active meanwhile.
+	Resume(ctx context.Context, userID, id primitive.ObjectID, at time.Time) (Session, error)
+	// End stops an active or paused session, closing its open pause, and returns
+	// the ended session. It returns ErrNotFound if the session is neither.
+	// Completing a focus session counts a pomodoro on the linked task in the
+	// same transaction, see Task.RecordPomodoro.
+	End(ctx context.Context, userID, id primitive.ObjectID, end EndSession) (Session, error)
+	// Stats returns the buckets of query that have at least one session or completed task.
+	Stats(ctx context.Context, query StatsQuery) ([]StatsBucket, error)
+}
+
+type UserRepository interface {
+	// Create returns ErrConflict when a user with the same Firebase UID exists.
+	Create(ctx context.Context, user *User) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (User, error)
+	FindByFirebaseUID(ctx context.Context, firebaseUID string) (User, error)
+	Update(ctx context.Context, id primitive.ObjectID, update UpdateUserDTO) error
+	// Patch replaces the editable fields of a user and returns the result.
+	Patch(ctx context.Context, id primitive.ObjectID, patch UserPatch) (User, error)
+	UpdateSettings(ctx context.Context, id primitive.ObjectID, settings Settings) error
+}
+
+type ProjectRepository interface {
+	Create(ctx context.Context, project *Project) error
+	FindByID(ctx context.Context, userID, id primitive.ObjectID) (Project, error)
+	// List returns the projects of the user ordered by name.
+	List(ctx context.Context, userID primitive.ObjectID) ([]Project, error)
+	Update(ctx context.Context, userID, id primitive.ObjectID, update UpdateProjectDTO) error
+	// Delete removes the project and detaches its tasks, which are kept.
+	Delete(ctx context.Context, userID, id primitive.ObjectID) error
+	Rollup(ctx context.Context, userID, id primitive.ObjectID) (ProjectRollup, error)
+}
@@ -7,29 +7,96 @@ import (
 )
 
 type Session struct {
-	ID        primitive.ObjectID `json:"id" bson:"_id"`
-	UserID    primitive.ObjectID `json:"user_id" bson:"user_id"`
-	TaskID    primitive.ObjectID `json:"task_id" bson:"task_id"`
-	StartedAt time.Time          `json:"started_at" bson:"started_at"`
-	EndedAt   time.Time          `json:"ended_at" bson:"ended_at"`
-	Duration  int16              `json:"duration" bson:"duration"`
-	Type      SessionType        `json:"type" bson:"type"`
-	Status    SessionStatus      `json:"status" bson:"status"`
+	ID        primitive.ObjectID  `json:"id" bson:"_id"`
+	UserID    primitive.ObjectID  `json:"user_id" bson:"user_id"`
+	TaskID    *primitive.ObjectID `json:"task_id,omitempty" bson:"task_id,omitempty"`
+	StartedAt time.Time           `json:"started_at" bson:"started_at"`
+	EndedAt   time.Time           `json:"ended_at" bson:"ended_at"`
+	Duration  int16               `json:"duration" bson:"duration"` // minutes
+	Type      SessionType         `json:"type" bson:"type"`
+	Status    SessionStatus       `json:"status" bson:"status"`
+	Pauses    []Pause             `json:"pauses,omitempty" bson:"pauses,omitempty"`
+}
+
+// Pause is an interval in which the timer of a session was stopped. ResumedAt
+// is nil while the session is still paused.
+type Pause struct {
+	PausedAt  time.Time  `json:"paused_at" bson:"paused_at"`
+	ResumedAt *time.Time `json:"resumed_at,omitempty" bson:"resumed_at,omitempty"`
+}
+
+// PausedFor returns the total paused time, counting an open pause up to now.
+func (s Session) PausedFor(now time.Time) time.Duration {
+	var paused time.Duration
+	for _, p := range s.Pauses {
+		if p.ResumedAt != nil {
+			paused += p.ResumedAt.Sub(p.PausedAt)
+		} else {
+			paused += now.Sub(p.PausedAt)
+		}
+	}
+
+	return paused
+}
+
+// Elapsed returns how long the timer has run by now, excluding paused time.
+// Ended sessions are measured up to EndedAt.
+func (s Session) Elapsed(now time.Time) time.Duration {
+	if !s.EndedAt.IsZero() {
+		now = s.EndedAt
+	}
+
+	return now.Sub(s.StartedAt) - s.PausedFor(now)
+}
+
+// Remaining returns how much of the duration is left by now.
+func (s Session) Remaining(now time.Time) time.Duration {
+	return max(time.Duration(s.Duration)*time.Minute-s.Elapsed(now), 0)
+}
+
+// Deadline returns when the session runs out if its timer keeps running from now on.
+func (s Session) Deadline() time.Time {
+	return s.StartedAt.Add(time.Duration(s.Duration)*time.Minute + s.PausedFor(time.Now()))
+}
+
+// SessionState is a session along with its timer as computed by the server.
+type SessionState struct {
+	Session
+	RemainingSeconds int64 `json:"remaining_seconds"`
+}
+
+func NewSessionState(session Session, now time.Time) SessionState {
+	return SessionState{
+		Session:          session,
+		RemainingSeconds: int64(session.Remaining(now).Seconds()),
+	}
+}
+
+// CurrentSession is the running session of a user with its linked task embedded.
+type CurrentSession struct {
+	SessionState
+	Task *Task `json:"task,omitempty"`
 }
 
 type CreateSessionDTO struct {
-	UserID    primitive.ObjectID  `json:"user_id" bson:"user_id" validate:"required"`
+	UserID    primitive.ObjectID  `json:"-" bson:"user_id" validate:"required"`
 	TaskID    *primitive.ObjectID `json:"task_id,omitempty" bson:"task_id,omitempty"`
 	StartedAt time.Time           `json:"started_at" bson:"started_at"`
 	EndedAt   time.Time           `json:"ended_at" bson:"ended_at"`
-	Duration  int16               `json:"duration" bson:"duration" validate:"required"`
-	Type      SessionType         `json:"type" bson:"type" validate:"required"`
+	Duration  int16               `json:"duration" bson:"duration" validate:"required,min=1"`
+	Type      SessionType         `json:"type" bson:"type" validate:"required,oneof=focus short_break long_break"`
 	Status    SessionStatus       `json:"status" bson:"status"`
 }
 
+type NextSessionDTO struct {
+	TaskID *primitive.ObjectID `json:"task_id,omitempty"`
+}
+
 type EndSession struct {
 	EndedAt time.Time     `json:"ended_at" bson:"ended_at"`
 	Status  SessionStatus `json:"status" bson:"status"`
+	// AutoCompleteTask marks the linked task completed once it reaches its estimate.
+	AutoCompleteTask bool `json:"-" bson:"-"`
 }
 
 type SessionType string
@@ -44,6 +111,7 @@ type SessionStatus string
 
 const (
 	SessionActive    SessionStatus = "active"
+	SessionPaused    SessionStatus = "paused"
 	SessionBreak     SessionStatus = "break"
 	SessionSkipped   SessionStatus = "skipped"
 	SessionCompleted SessionStatus = "completed"
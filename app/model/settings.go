@@ -0,0 +1,71 @@
+package model
+
+import "time"
+
+// Settings are the pomodoro preferences of a user. Lengths are in minutes.
+type Settings struct {
+	FocusMinutes      int16  `json:"focus_minutes" bson:"focus_minutes" validate:"min=1,max=180"`
+	ShortBreakMinutes int16  `json:"short_break_minutes" bson:"short_break_minutes" validate:"min=1,max=60"`
+	LongBreakMinutes  int16  `json:"long_break_minutes" bson:"long_break_minutes" validate:"min=1,max=120"`
+	LongBreakInterval int    `json:"long_break_interval" bson:"long_break_interval" validate:"min=1,max=12"`
+	IdleResetMinutes  int16  `json:"idle_reset_minutes" bson:"idle_reset_minutes" validate:"min=1,max=1440"`
+	Timezone          string `json:"timezone" bson:"timezone"`
+	// AutoCompleteTasks completes a task when its completed pomodoros reach the estimate.
+	AutoCompleteTasks bool `json:"auto_complete_tasks" bson:"auto_complete_tasks"`
+}
+
+var DefaultSettings = Settings{
+	FocusMinutes:      25,
+	ShortBreakMinutes: 5,
+	LongBreakMinutes:  15,
+	LongBreakInterval: 4,
+	IdleResetMinutes:  30,
+	Timezone:          "UTC",
+}
+
+// WithDefaults fills the fields that were never set, as users created before
+// settings existed have none stored.
+func (s Settings) WithDefaults() Settings {
+	if s.FocusMinutes == 0 {
+		s.FocusMinutes = DefaultSettings.FocusMinutes
+	}
+	if s.ShortBreakMinutes == 0 {
+		s.ShortBreakMinutes = DefaultSettings.ShortBreakMinutes
+	}
+	if s.LongBreakMinutes == 0 {
+		s.LongBreakMinutes = DefaultSettings.LongBreakMinutes
+	}
+	if s.LongBreakInterval == 0 {
+		s.LongBreakInterval = DefaultSettings.LongBreakInterval
+	}
+	if s.IdleResetMinutes == 0 {
+		s.IdleResetMinutes = DefaultSettings.IdleResetMinutes
+	}
+	if s.Timezone == "" {
+		s.Timezone = DefaultSettings.Timezone
+	}
+
+	return s
+}
+
+// Location returns the time zone of the user, falling back to UTC.
+func (s Settings) Location() *time.Location {
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+
+	return loc
+}
+
+func (s Settings) CycleRules() CycleRules {
+	s = s.WithDefaults()
+
+	return CycleRules{
+		FocusMinutes:      s.FocusMinutes,
+		ShortBreakMinutes: s.ShortBreakMinutes,
+		LongBreakMinutes:  s.LongBreakMinutes,
+		LongBreakInterval: s.LongBreakInterval,
+		IdleReset:         time.Duration(s.IdleResetMinutes) * time.Minute,
+	}
+}
@@ -0,0 +1,65 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+type StatsPeriod string
+
+const (
+	StatsDaily   StatsPeriod = "daily"
+	StatsWeekly  StatsPeriod = "weekly"
+	StatsMonthly StatsPeriod = "monthly"
+)
+
+// Key returns the bucket t falls into, in the location of t.
+func (p StatsPeriod) Key(t time.Time) string {
+	switch p {
+	case StatsWeekly:
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	case StatsMonthly:
+		return t.Format("2006-01")
+	default:
+		return t.Format("2006-01-02")
+	}
+}
+
+// MaxEnd returns the latest end of a stats range starting at start, capping
+// every response at 366 days, 104 weeks or 60 months.
+func (p StatsPeriod) MaxEnd(start time.Time) time.Time {
+	switch p {
+	case StatsWeekly:
+		return start.AddDate(0, 0, 7*104)
+	case StatsMonthly:
+		return start.AddDate(0, 60, 0)
+	default:
+		return start.AddDate(0, 0, 366)
+	}
+}
+
+type SessionCounts struct {
+	Completed int64 `json:"completed" bson:"completed"`
+	Skipped   int64 `json:"skipped" bson:"skipped"`
+}
+
+// StatsBucket aggregates the sessions of one local day ("2006-01-02"),
+// ISO week ("2006-W01") or month ("2006-01").
+type StatsBucket struct {
+	Period         string                        `json:"period"`
+	FocusMinutes   float64                       `json:"focus_minutes"`
+	Sessions       map[SessionType]SessionCounts `json:"sessions"`
+	TasksCompleted int64                         `json:"tasks_completed"`
+}
+
+func NewStatsBucket(period string) StatsBucket {
+	return StatsBucket{
+		Period: period,
+		Sessions: map[SessionType]SessionCounts{
+			Focus:      {},
+			ShortBreak: {},
+			LongBreak:  {},
+		},
+	}
+}
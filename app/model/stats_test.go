@@ -0,0 +1,60 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStatsPeriodKey(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		period StatsPeriod
+		at     time.Time
+		want   string
+	}{
+		{StatsDaily, time.Date(2026, 3, 1, 23, 59, 0, 0, time.UTC), "2026-03-01"},
+		{StatsDaily, time.Date(2026, 3, 1, 20, 0, 0, 0, time.UTC).In(jakarta), "2026-03-02"},
+		{StatsDaily, time.Date(2026, 3, 1, 2, 0, 0, 0, time.UTC).In(newYork), "2026-02-28"},
+		// ISO weeks belong to the year of their Thursday.
+		{StatsWeekly, time.Date(2025, 12, 29, 0, 0, 0, 0, time.UTC), "2026-W01"},
+		{StatsWeekly, time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC), "2026-W53"},
+		{StatsWeekly, time.Date(2027, 1, 4, 0, 0, 0, 0, time.UTC), "2027-W01"},
+		{StatsWeekly, time.Date(2026, 3, 8, 23, 0, 0, 0, time.UTC), "2026-W10"},
+		{StatsWeekly, time.Date(2026, 3, 8, 23, 0, 0, 0, time.UTC).In(jakarta), "2026-W11"},
+		{StatsMonthly, time.Date(2026, 1, 31, 12, 0, 0, 0, time.UTC), "2026-01"},
+		{StatsMonthly, time.Date(2026, 3, 1, 2, 0, 0, 0, time.UTC).In(newYork), "2026-02"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.period.Key(tt.at); got != tt.want {
+			t.Errorf("%s.Key(%s) = %q, want %q", tt.period, tt.at, got, tt.want)
+		}
+	}
+}
+
+func TestStatsPeriodMaxEnd(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		period StatsPeriod
+		want   time.Time
+	}{
+		{StatsDaily, time.Date(2027, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{StatsWeekly, time.Date(2027, 12, 30, 0, 0, 0, 0, time.UTC)},
+		{StatsMonthly, time.Date(2031, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		if got := tt.period.MaxEnd(start); !got.Equal(tt.want) {
+			t.Errorf("%s.MaxEnd() = %s, want %s", tt.period, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"slices"
+	"time"
+)
+
+// taskTransitions lists the statuses an update may move a task to from each
+// status. Tasks enter and leave the trash through Delete and Restore only.
+var taskTransitions = map[TaskStatus][]TaskStatus{
+	TaskPending:    {TaskInProgress},
+	TaskInProgress: {TaskPending, TaskCompleted},
+	TaskCompleted:  {TaskPending, TaskInProgress},
+}
+
+// CanTransitionTo reports whether a task in status s may be updated to next.
+// Keeping the current status is allowed for every status outside the trash.
+func (s TaskStatus) CanTransitionTo(next TaskStatus) bool {
+	transitions, ok := taskTransitions[s]
+	return ok && (s == next || slices.Contains(transitions, next))
+}
+
+// TransitionSources returns the statuses a task may be updated to next from.
+func TransitionSources(next TaskStatus) []TaskStatus {
+	var sources []TaskStatus
+	for s := range taskTransitions {
+		if s.CanTransitionTo(next) {
+			sources = append(sources, s)
+		}
+	}
+	slices.Sort(sources)
+
+	return sources
+}
+
+// SetStatus moves the task to status, stamping CompletedAt when it gets
+// completed and clearing it when it is reopened.
+func (t *Task) SetStatus(status TaskStatus, at time.Time) {
+	switch {
+	case status != TaskCompleted:
+		t.CompletedAt = nil
+	case t.Status != TaskCompleted:
+		t.CompletedAt = &at
+	}
+	t.Status = status
+}
+
+// CompletionTime returns when a completed task was completed, or nil for any
+// other status. Tasks completed before CompletedAt was recorded fall back to
+// their last update.
+func (t Task) CompletionTime() *time.Time {
+	switch {
+	case t.Status != TaskCompleted:
+		return nil
+	case t.CompletedAt != nil:
+		return t.CompletedAt
+	default:
+		return &t.UpdatedAt
+	}
+}
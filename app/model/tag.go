@@ -0,0 +1,38 @@
+package model
+
+import "strings"
+
+// TagCount is a tag with usage statistics over the non-deleted tasks of a user.
+type TagCount struct {
+	Tag                string `json:"tag" bson:"_id"`
+	Tasks              int64  `json:"tasks" bson:"tasks"`
+	CompletedTasks     int64  `json:"completed_tasks" bson:"completed_tasks"`
+	CompletedPomodoros int64  `json:"completed_pomodoros" bson:"completed_pomodoros"`
+}
+
+type RenameTagDTO struct {
+	From string `json:"from" validate:"required,max=32"`
+	To   string `json:"to" validate:"required,max=32"`
+}
+
+// NormalizeTag trims and lowercases tag, so tags compare regardless of case.
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// NormalizeTags normalizes every tag and drops empty and repeated ones,
+// keeping the first occurrence order.
+func NormalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	seen := map[string]bool{}
+	for _, tag := range tags {
+		tag = NormalizeTag(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+
+	return normalized
+}
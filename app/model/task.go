@@ -7,45 +7,82 @@ import (
 )
 
 type Task struct {
-	ID                 primitive.ObjectID `json:"id" bson:"_id"`
-	UserID             primitive.ObjectID `json:"user_id" bson:"user_id"`
-	Title              string             `json:"title" bson:"title"`
-	Description        *string            `json:"description" bson:"description"`
-	AssignedAt         time.Time          `json:"assigned_at" bson:"assigned_at"`
-	Status             TaskStatus         `json:"status" bson:"status"`
-	EstimatedPomodoros int16              `json:"estimated_pomodoros" bson:"estimated_pomodoros" validate:"min=1"`
-	CompletedPomodoros int16              `json:"completed_pomodoros" bson:"completed_pomodoros"`
-	CreatedAt          time.Time          `json:"created_at,omitempty" bson:"created_at,omitempty"`
-	UpdatedAt          time.Time          `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
-	DeletedAt          *time.Time         `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
+	ID          primitive.ObjectID  `json:"id" bson:"_id"`
+	UserID      primitive.ObjectID  `json:"user_id" bson:"user_id"`
+	ProjectID   *primitive.ObjectID `json:"project_id,omitempty" bson:"project_id,omitempty"`
+	Title       string              `json:"title" bson:"title"`
+	Description *string             `json:"description" bson:"description"`
+	AssignedAt  time.Time           `json:"assigned_at" bson:"assigned_at"`
+	DueAt       *time.Time          `json:"due_at,omitempty" bson:"due_at,omitempty"`
+	Priority    Priority            `json:"priority" bson:"priority"`
+	// Position orders the tasks of a user manually, lowest first.
+	Position           int64           `json:"position" bson:"position"`
+	Status             TaskStatus      `json:"status" bson:"status"`
+	EstimatedPomodoros int16           `json:"estimated_pomodoros" bson:"estimated_pomodoros" validate:"min=1"`
+	CompletedPomodoros int16           `json:"completed_pomodoros" bson:"completed_pomodoros"`
+	Tags               []string        `json:"tags,omitempty" bson:"tags,omitempty"`
+	Checklist          []ChecklistItem `json:"checklist,omitempty" bson:"checklist,omitempty"`
+	Recurrence         *Recurrence     `json:"recurrence,omitempty" bson:"recurrence,omitempty"`
+	// SeriesID is the first task of the series a generated occurrence belongs to.
+	SeriesID *primitive.ObjectID `json:"series_id,omitempty" bson:"series_id,omitempty"`
+	// NextCreated is set once the following occurrence has been generated.
+	NextCreated bool      `json:"-" bson:"next_created,omitempty"`
+	CreatedAt   time.Time `json:"created_at,omitempty" bson:"created_at,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+	// CompletedAt is when the task was last completed, it is cleared when the task is reopened.
+	CompletedAt *time.Time `json:"completed_at,omitempty" bson:"completed_at,omitempty"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
+	// StatusBeforeDelete is the status a trashed task gets back when restored.
+	StatusBeforeDelete TaskStatus `json:"status_before_delete,omitempty" bson:"status_before_delete,omitempty"`
+}
+
+// RecordPomodoro counts a completed focus session on the task. The first one
+// moves a pending task to in progress, and with autoComplete the task is
+// completed once it reaches its estimate.
+func (t *Task) RecordPomodoro(autoComplete bool, at time.Time) {
+	t.CompletedPomodoros++
+	if t.Status == TaskPending {
+		t.SetStatus(TaskInProgress, at)
+	}
+	if autoComplete && t.Status == TaskInProgress && t.CompletedPomodoros >= t.EstimatedPomodoros {
+		t.SetStatus(TaskCompleted, at)
+	}
+	t.UpdatedAt = at
 }
 
 type CreateTaskDTO struct {
-	UserID             primitive.ObjectID `json:"user_id" bson:"user_id" validate:"required"`
-	Title              string             `json:"title" bson:"title" validate:"required"`
-	Description        *string            `json:"description,omitempty" bson:"description,omitempty"`
-	AssignedAt         *time.Time         `json:"assigned_at,omitempty" bson:"assigned_at,omitempty"`
-	Status             TaskStatus         `json:"status" bson:"status"`
-	EstimatedPomodoros *int16             `json:"estimated_pomodoros" bson:"estimated_pomodoros" validate:"omitempty,min=1"`
-	CompletedPomodoros int16              `json:"completed_pomodoros" bson:"completed_pomodoros"`
-	CreatedAt          time.Time          `json:"created_at,omitempty" bson:"created_at,omitempty"`
-	UpdatedAt          time.Time          `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
-	DeletedAt          time.Time          `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
+	UserID             primitive.ObjectID  `json:"-" bson:"user_id" validate:"required"`
+	ProjectID          *primitive.ObjectID `json:"project_id,omitempty" bson:"project_id,omitempty"`
+	Title              string              `json:"title" bson:"title" validate:"required"`
+	Description        *string             `json:"description,omitempty" bson:"description,omitempty"`
+	AssignedAt         *time.Time          `json:"assigned_at,omitempty" bson:"assigned_at,omitempty"`
+	DueAt              *time.Time          `json:"due_at,omitempty" bson:"due_at,omitempty"`
+	Priority           Priority            `json:"priority" bson:"priority" validate:"min=0,max=3"`
+	Status             TaskStatus          `json:"status" bson:"status"`
+	EstimatedPomodoros *int16              `json:"estimated_pomodoros" bson:"estimated_pomodoros" validate:"omitempty,min=1"`
+	CompletedPomodoros int16               `json:"completed_pomodoros" bson:"completed_pomodoros"`
+	Tags               []string            `json:"tags,omitempty" bson:"tags,omitempty" validate:"max=20,dive,max=32"`
+	Recurrence         *Recurrence         `json:"recurrence,omitempty" bson:"recurrence,omitempty"`
+	CreatedAt          time.Time           `json:"created_at,omitempty" bson:"created_at,omitempty"`
+	UpdatedAt          time.Time           `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+	DeletedAt          time.Time           `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
 }
 
 type UpdateTaskDTO struct {
-	Title              *string     `json:"title,omitempty" bson:"title,omitempty"`
-	Description        *string     `json:"description,omitempty" bson:"description,omitempty"`
-	AssignedAt         *time.Time  `json:"assigned_at,omitempty" bson:"assigned_at,omitempty"`
-	Status             *TaskStatus `json:"status,omitempty" bson:"status,omitempty"`
+	ProjectID   *primitive.ObjectID `json:"project_id,omitempty" bson:"project_id,omitempty"`
+	Title       *string             `json:"title,omitempty" bson:"title,omitempty"`
+	Description *string             `json:"description,omitempty" bson:"description,omitempty"`
+	AssignedAt  *time.Time          `json:"assigned_at,omitempty" bson:"assigned_at,omitempty"`
+	DueAt       *time.Time          `json:"due_at,omitempty" bson:"due_at,omitempty"`
+	Priority    *Priority           `json:"priority,omitempty" bson:"priority,omitempty" validate:"omitempty,min=0,max=3"`
+	// Status must be a legal transition from the current status, see TaskStatus.CanTransitionTo.
+	Status             *TaskStatus `json:"status,omitempty" bson:"status,omitempty" validate:"omitempty,oneof=pending in_progress completed"`
 	EstimatedPomodoros *int16      `json:"estimated_pomodoros,omitempty" bson:"estimated_pomodoros,omitempty" validate:"omitempty,min=1"`
 	CompletedPomodoros *int16      `json:"completed_pomodoros,omitempty" bson:"completed_pomodoros,omitempty"`
-	UpdatedAt          time.Time   `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
-}
-
-type DeleteTaskDTO struct {
-	Status    TaskStatus `json:"status" bson:"status"`
-	DeletedAt time.Time  `json:"deleted_at" bson:"deleted_at"`
+	// Tags replaces all tags of the task, an empty list removes them.
+	Tags       *[]string   `json:"tags,omitempty" bson:"tags,omitempty" validate:"omitempty,max=20,dive,max=32"`
+	Recurrence *Recurrence `json:"recurrence,omitempty" bson:"recurrence,omitempty"`
+	UpdatedAt  time.Time   `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
 }
 
 type TaskStatus string
@@ -56,3 +93,29 @@ const (
 	TaskCompleted  TaskStatus = "completed"
 	TaskDeleted    TaskStatus = "deleted"
 )
+
+// Priority ranks tasks from PriorityNone up to PriorityHigh.
+type Priority int8
+
+const (
+	PriorityNone Priority = iota
+	PriorityLow
+	PriorityMedium
+	PriorityHigh
+)
+
+// RemainingPomodoros returns how many estimated pomodoros are left on an open task.
+func (t Task) RemainingPomodoros() int16 {
+	if t.Status == TaskCompleted || t.Status == TaskDeleted {
+		return 0
+	}
+
+	return max(t.EstimatedPomodoros-t.CompletedPomodoros, 0)
+}
+
+// DayPlan lists the tasks assigned to or due on a day of the user.
+type DayPlan struct {
+	Date               string `json:"date"`
+	Tasks              []Task `json:"tasks"`
+	RemainingPomodoros int64  `json:"remaining_pomodoros"`
+}
@@ -11,11 +11,12 @@ type User struct {
 	FirebaseUID string             `json:"firebase_uid" bson:"firebase_uid" validate:"required"`
 	Email       string             `json:"email" bson:"email" validate:"required"`
 	Name        string             `json:"name" bson:"name" validate:"required"`
+	Settings    Settings           `json:"settings" bson:"settings"`
 	CreatedAt   time.Time          `json:"created_at" bson:"created_at"`
 }
 
 type CreateUserDTO struct {
-	FirebaseUID string    `json:"firebase_uid" bson:"firebase_uid" validate:"required"`
+	FirebaseUID string    `json:"-" bson:"firebase_uid" validate:"required"`
 	Email       string    `json:"email" bson:"email" validate:"required"`
 	Name        string    `json:"name" bson:"name" validate:"required"`
 	CreatedAt   time.Time `json:"created_at" bson:"created_at"`
@@ -0,0 +1,107 @@
+// Package realtime pushes session events to the connected devices of a user.
+package realtime
+
+import (
+	"sync"
+	"time"
+
+	"github.com/anggara-26/pomodoro-backend.git/app/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type EventType string
+
+const (
+	EventStart  EventType = "start"
+	EventPause  EventType = "pause"
+	EventResume EventType = "resume"
+	EventEnd    EventType = "end"
+	EventSkip   EventType = "skip"
+	EventTick   EventType = "tick"
+)
+
+type Event struct {
+	Type    EventType          `json:"type"`
+	Session model.SessionState `json:"session"`
+	At      time.Time          `json:"at"`
+}
+
+func NewEvent(t EventType, session model.Session, now time.Time) Event {
+	return Event{
+		Type:    t,
+		Session: model.NewSessionState(session, now),
+		At:      now,
+	}
+}
+
+// EndEventType returns the event announcing that a session ended with status.
+func EndEventType(status model.SessionStatus) EventType {
+	if status == model.SessionSkipped {
+		return EventSkip
+	}
+
+	return EventEnd
+}
+
+// Hub fans events out to every subscriber of a user. Publish must not block on
+// slow subscribers.
+type Hub interface {
+	Publish(userID primitive.ObjectID, event Event)
+	// Subscribe returns a channel of the events of userID and a function that
+	// ends the subscription and closes the channel.
+	Subscribe(userID primitive.ObjectID) (<-chan Event, func())
+}
+
+// subscriberBuffer is how many events a subscriber may lag behind before
+// further events are dropped for it.
+const subscriberBuffer = 16
+
+// LocalHub is a Hub that only reaches subscribers of the current process. A
+// broker backed Hub can replace it once the API runs on several processes.
+type LocalHub struct {
+	mu          sync.Mutex
+	subscribers map[primitive.ObjectID]map[chan Event]struct{}
+}
+
+func NewLocalHub() *LocalHub {
+	return &LocalHub{subscribers: map[primitive.ObjectID]map[chan Event]struct{}{}}
+}
+
+func (h *LocalHub) Publish(userID primitive.ObjectID, event Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.subscribers[userID] {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+func (h *LocalHub) Subscribe(userID primitive.ObjectID) (<-chan Event, func()) {
+	ch := make(chan Event, subscriberBuffer)
+
+	h.mu.Lock()
+	if h.subscribers[userID] == nil {
+		h.subscribers[userID] = map[chan Event]struct{}{}
+	}
+	h.subscribers[userID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+
+			delete(h.subscribers[userID], ch)
+			if len(h.subscribers[userID]) == 0 {
+				delete(h.subscribers, userID)
+			}
+			close(ch)
+		})
+	}
+
+	return ch, unsubscribe
+}
@@ -0,0 +1,91 @@
+package realtime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/anggara-26/pomodoro-backend.git/app/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestLocalHubFansOutPerUser(t *testing.T) {
+	hub := NewLocalHub()
+	userID, otherID := primitive.NewObjectID(), primitive.NewObjectID()
+
+	phone, unsubscribePhone := hub.Subscribe(userID)
+	defer unsubscribePhone()
+	laptop, unsubscribeLaptop := hub.Subscribe(userID)
+	defer unsubscribeLaptop()
+	other, unsubscribeOther := hub.Subscribe(otherID)
+	defer unsubscribeOther()
+
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	event := NewEvent(EventStart, model.Session{ID: primitive.NewObjectID(), UserID: userID, StartedAt: now, Duration: 25}, now)
+	hub.Publish(userID, event)
+
+	for name, events := range map[string]<-chan Event{"phone": phone, "laptop": laptop} {
+		select {
+		case got := <-events:
+			if got.Type != EventStart || got.Session.ID != event.Session.ID || got.Session.RemainingSeconds != 25*60 {
+				t.Errorf("%s got %+v, want %+v", name, got, event)
+			}
+		default:
+			t.Errorf("%s got no event", name)
+		}
+	}
+	select {
+	case got := <-other:
+		t.Errorf("another user got %+v", got)
+	default:
+	}
+}
+
+func TestLocalHubDropsForSlowSubscribers(t *testing.T) {
+	hub := NewLocalHub()
+	userID := primitive.NewObjectID()
+	events, unsubscribe := hub.Subscribe(userID)
+	defer unsubscribe()
+
+	// Publish must return even though nobody reads the events.
+	for i := 0; i < subscriberBuffer+5; i++ {
+		hub.Publish(userID, Event{Type: EventTick})
+	}
+
+	if len(events) != subscriberBuffer {
+		t.Errorf("buffered events = %d, want %d", len(events), subscriberBuffer)
+	}
+}
+
+func TestLocalHubUnsubscribe(t *testing.T) {
+	hub := NewLocalHub()
+	userID := primitive.NewObjectID()
+	events, unsubscribe := hub.Subscribe(userID)
+
+	unsubscribe()
+	unsubscribe()
+
+	if _, ok := <-events; ok {
+		t.Error("channel is still open after unsubscribing")
+	}
+	hub.Publish(userID, Event{Type: EventTick})
+	if len(hub.subscribers) != 0 {
+		t.Errorf("subscribers = %v, want none", hub.subscribers)
+	}
+}
+
+func TestEndEventType(t *testing.T) {
+	tests := []struct {
+		status model.SessionStatus
+		want   EventType
+	}{
+		{model.SessionCompleted, EventEnd},
+		{model.SessionSkipped, EventSkip},
+		{model.SessionBreak, EventEnd},
+	}
+
+	for _, tt := range tests {
+		if got := EndEventType(tt.status); got != tt.want {
+			t.Errorf("EndEventType(%s) = %s, want %s", tt.status, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+// Package memory implements the model repositories in process memory. It is
+// meant for tests and local development, nothing is persisted.
+package memory
+
+import (
+	"sync"
+
+	"github.com/anggara-26/pomodoro-backend.git/app/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Store holds the documents shared by the repositories of this package.
+type Store struct {
+	mu       sync.RWMutex
+	tasks    map[primitive.ObjectID]model.Task
+	users    map[primitive.ObjectID]model.User
+	sessions map[primitive.ObjectID]model.Session
+	projects map[primitive.ObjectID]model.Project
+}
+
+func NewStore() *Store {
+	return &Store{
+		tasks:    map[primitive.ObjectID]model.Task{},
+		users:    map[primitive.ObjectID]model.User{},
+		sessions: map[primitive.ObjectID]model.Session{},
+		projects: map[primitive.ObjectID]model.Project{},
+	}
+}
+
+func NewModels() model.Models {
+	store := NewStore()
+
+	return model.Models{
+		Tasks:    NewTaskRepository(store),
+		Users:    NewUserRepository(store),
+		Projects: NewProjectRepository(store),
+		Sessions: NewSessionRepository(store),
+	}
+}
@@ -0,0 +1,137 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/anggara-26/pomodoro-backend.git/app/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ProjectRepository struct {
+	store *Store
+}
+
+func NewProjectRepository(store *Store) *ProjectRepository {
+	return &ProjectRepository{store: store}
+}
+
+func (r *ProjectRepository) Create(ctx context.Context, project *model.Project) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if project.ID.IsZero() {
+		project.ID = primitive.NewObjectID()
+	}
+	r.store.projects[project.ID] = *project
+
+	return nil
+}
+
+func (r *ProjectRepository) FindByID(ctx context.Context, userID, id primitive.ObjectID) (model.Project, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	project, ok := r.store.projects[id]
+	if !ok || project.UserID != userID {
+		return model.Project{}, model.ErrNotFound
+	}
+
+	return project, nil
+}
+
+func (r *ProjectRepository) List(ctx context.Context, userID primitive.ObjectID) ([]model.Project, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var projects []model.Project
+	for _, project := range r.store.projects {
+		if project.UserID == userID {
+			projects = append(projects, project)
+		}
+	}
+	sort.Slice(projects, func(i, j int) bool {
+		if projects[i].Name != projects[j].Name {
+			return projects[i].Name < projects[j].Name
+		}
+		return projects[i].ID.Hex() < projects[j].ID.Hex()
+	})
+
+	return projects, nil
+}
+
+func (r *ProjectRepository) Update(ctx context.Context, userID, id primitive.ObjectID, update model.UpdateProjectDTO) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	project, ok := r.store.projects[id]
+	if !ok || project.UserID != userID {
+		return model.ErrNotFound
+	}
+
+	if update.Name != nil {
+		project.Name = *update.Name
+	}
+	if update.Description != nil {
+		project.Description = update.Description
+	}
+	project.UpdatedAt = update.UpdatedAt
+	r.store.projects[id] = project
+
+	return nil
+}
+
+func (r *ProjectRepository) Delete(ctx context.Context, userID, id primitive.ObjectID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	project, ok := r.store.projects[id]
+	if !ok || project.UserID != userID {
+		return model.ErrNotFound
+	}
+	delete(r.store.projects, id)
+
+	for taskID, task := range r.store.tasks {
+		if task.UserID == userID && task.ProjectID != nil && *task.ProjectID == id {
+			task.ProjectID = nil
+			r.store.tasks[taskID] = task
+		}
+	}
+
+	return nil
+}
+
+func (r *ProjectRepository) Rollup(ctx context.Context, userID, id primitive.ObjectID) (model.ProjectRollup, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	rollup := model.ProjectRollup{ProjectID: id}
+	inProject := map[primitive.ObjectID]bool{}
+	for _, task := range r.store.tasks {
+		if task.UserID != userID || task.ProjectID == nil || *task.ProjectID != id {
+			continue
+		}
+		inProject[task.ID] = true
+		if task.Status == model.TaskDeleted {
+			continue
+		}
+
+		rollup.Tasks++
+		if task.Status == model.TaskCompleted {
+			rollup.CompletedTasks++
+		}
+		rollup.EstimatedPomodoros += int64(task.EstimatedPomodoros)
+		rollup.CompletedPomodoros += int64(task.CompletedPomodoros)
+	}
+
+	for _, session := range r.store.sessions {
+		if session.UserID != userID || session.Type != model.Focus || session.TaskID == nil || !inProject[*session.TaskID] {
+			continue
+		}
+		if session.Status == model.SessionCompleted || session.Status == model.SessionSkipped {
+			rollup.FocusMinutes += session.Elapsed(session.EndedAt).Minutes()
+		}
+	}
+
+	return rollup, nil
+}
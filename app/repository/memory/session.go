@@ -0,0 +1,273 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/anggara-26/pomodoro-backend.git/app/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type SessionRepository struct {
+	store *Store
+}
+
+func NewSessionRepository(store *Store) *SessionRepository {
+	return &SessionRepository{store: store}
+}
+
+func (r *SessionRepository) Start(ctx context.Context, session *model.Session) ([]model.Session, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var demoted []model.Session
+	for id, running := range r.store.sessions {
+		if running.UserID == session.UserID && (running.Status == model.SessionActive || running.Status == model.SessionPaused) {
+			running.Status = model.SessionBreak
+			r.store.sessions[id] = running
+			demoted = append(demoted, running)
+		}
+	}
+
+	if session.ID.IsZero() {
+		session.ID = primitive.NewObjectID()
+	}
+	r.store.sessions[session.ID] = *session
+
+	return demoted, nil
+}
+
+func (r *SessionRepository) FindByID(ctx context.Context, userID, id primitive.ObjectID) (model.Session, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	session, ok := r.store.sessions[id]
+	if !ok || session.UserID != userID {
+		return model.Session{}, model.ErrNotFound
+	}
+
+	return session, nil
+}
+
+func (r *SessionRepository) FindCurrent(ctx context.Context, userID primitive.ObjectID) (model.Session, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var current model.Session
+	found := false
+	for _, session := range r.store.sessions {
+		if session.UserID != userID || (session.Status != model.SessionActive && session.Status != model.SessionPaused) {
+			continue
+		}
+		if !found || session.Status == model.SessionActive && current.Status != model.SessionActive ||
+			session.Status == current.Status && session.StartedAt.After(current.StartedAt) {
+			current, found = session, true
+		}
+	}
+	if !found {
+		return model.Session{}, model.ErrNotFound
+	}
+
+	return current, nil
+}
+
+func (r *SessionRepository) List(ctx context.Context, f model.SessionFilter) ([]model.Session, int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var sessions []model.Session
+	for _, session := range r.store.sessions {
+		switch {
+		case session.UserID != f.UserID:
+		case f.Type != "" && session.Type != f.Type:
+		case f.Status != "" && session.Status != f.Status:
+		case f.TaskID != nil && (session.TaskID == nil || *session.TaskID != *f.TaskID):
+		case f.StartDate != nil && session.StartedAt.Before(*f.StartDate):
+		case f.EndDate != nil && session.StartedAt.After(*f.EndDate):
+		default:
+			sessions = append(sessions, session)
+		}
+	}
+
+	less := func(a, b model.Session) bool {
+		switch f.SortBy {
+		case "ended_at":
+			if !a.EndedAt.Equal(b.EndedAt) {
+				return a.EndedAt.Before(b.EndedAt)
+			}
+		case "duration":
+			if a.Duration != b.Duration {
+				return a.Duration < b.Duration
+			}
+		default:
+			if !a.StartedAt.Equal(b.StartedAt) {
+				return a.StartedAt.Before(b.StartedAt)
+			}
+		}
+		return a.ID.Hex() < b.ID.Hex()
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		if f.SortDesc {
+			return less(sessions[j], sessions[i])
+		}
+		return less(sessions[i], sessions[j])
+	})
+
+	return paginate(sessions, f.Skip, f.Limit), int64(len(sessions)), nil
+}
+
+func (r *SessionRepository) ListEnded(ctx context.Context, userID primitive.ObjectID, limit int64) ([]model.Session, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var sessions []model.Session
+	for _, session := range r.store.sessions {
+		if session.UserID == userID && (session.Status == model.SessionCompleted || session.Status == model.SessionSkipped) {
+			sessions = append(sessions, session)
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].StartedAt.After(sessions[j].StartedAt) })
+
+	return paginate(sessions, 0, limit), nil
+}
+
+func (r *SessionRepository) ListActive(ctx context.Context) ([]model.Session, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var sessions []model.Session
+	for _, session := range r.store.sessions {
+		if session.Status == model.SessionActive {
+			sessions = append(sessions, session)
+		}
+	}
+
+	return sessions, nil
+}
+
+func (r *SessionRepository) Pause(ctx context.Context, userID, id primitive.ObjectID, at time.Time) (model.Session, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	session, ok := r.store.sessions[id]
+	if !ok || session.UserID != userID || session.Status != model.SessionActive {
+		return model.Session{}, model.ErrNotFound
+	}
+	session.Status = model.SessionPaused
+	session.Pauses = append(session.Pauses, model.Pause{PausedAt: at})
+	r.store.sessions[id] = session
+
+	return session, nil
+}
+
+func (r *SessionRepository) Resume(ctx context.Context, userID, id primitive.ObjectID, at time.Time) (model.Session, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	session, ok := r.store.sessions[id]
+	if !ok || session.UserID != userID || session.Status != model.SessionPaused {
+		return model.Session{}, model.ErrNotFound
+	}
+	for _, running := range r.store.sessions {
+		if running.UserID == userID && running.Status == model.SessionActive {
+			return model.Session{}, model.ErrConflict
+		}
+	}
+	session.Status = model.SessionActive
+	session.Pauses = closePauses(session.Pauses, at)
+	r.store.sessions[id] = session
+
+	return session, nil
+}
+
+func (r *SessionRepository) End(ctx context.Context, userID, id primitive.ObjectID, end model.EndSession) (model.Session, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	session, ok := r.store.sessions[id]
+	if !ok || session.UserID != userID || (session.Status != model.SessionActive && session.Status != model.SessionPaused) {
+		return model.Session{}, model.ErrNotFound
+	}
+	session.EndedAt = end.EndedAt
+	session.Status = end.Status
+	session.Pauses = closePauses(session.Pauses, end.EndedAt)
+	r.store.sessions[id] = session
+
+	if session.Type == model.Focus && session.Status == model.SessionCompleted && session.TaskID != nil {
+		task, ok := r.store.tasks[*session.TaskID]
+		if ok && task.UserID == userID && task.Status != model.TaskDeleted {
+			task.RecordPomodoro(end.AutoCompleteTask, end.EndedAt)
+			r.store.tasks[task.ID] = task
+		}
+	}
+
+	return session, nil
+}
+
+// closePauses returns a copy of pauses with the open pause resumed at at.
+func closePauses(pauses []model.Pause, at time.Time) []model.Pause {
+	closed := make([]model.Pause, len(pauses))
+	for i, p := range pauses {
+		if p.ResumedAt == nil {
+			p.ResumedAt = &at
+		}
+		closed[i] = p
+	}
+
+	return closed
+}
+
+func (r *SessionRepository) Stats(ctx context.Context, q model.StatsQuery) ([]model.StatsBucket, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	buckets := map[string]*model.StatsBucket{}
+	bucket := func(key string) *model.StatsBucket {
+		b, ok := buckets[key]
+		if !ok {
+			bucket := model.NewStatsBucket(key)
+			b = &bucket
+			buckets[key] = b
+		}
+		return b
+	}
+	inRange := func(t time.Time) bool {
+		return !t.Before(q.Start) && t.Before(q.End)
+	}
+
+	for _, session := range r.store.sessions {
+		if session.UserID != q.UserID || !inRange(session.StartedAt) {
+			continue
+		}
+		if session.Status != model.SessionCompleted && session.Status != model.SessionSkipped {
+			continue
+		}
+
+		b := bucket(q.Period.Key(session.StartedAt.In(q.Location)))
+		counts := b.Sessions[session.Type]
+		if session.Status == model.SessionCompleted {
+			counts.Completed++
+		} else {
+			counts.Skipped++
+		}
+		b.Sessions[session.Type] = counts
+		if session.Type == model.Focus {
+			b.FocusMinutes += session.Elapsed(session.EndedAt).Minutes()
+		}
+	}
+
+	for _, task := range r.store.tasks {
+		if completedAt := task.CompletionTime(); task.UserID == q.UserID && completedAt != nil && inRange(*completedAt) {
+			bucket(q.Period.Key(completedAt.In(q.Location))).TasksCompleted++
+		}
+	}
+
+	result := make([]model.StatsBucket, 0, len(buckets))
+	for _, b := range buckets {
+		result = append(result, *b)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Period < result[j].Period })
+
+	return result, nil
+}
@@ -0,0 +1,726 @@
+package memory
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/anggara-26/pomodoro-backend.git/app/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type TaskRepository struct {
+	store *Store
+}
+
+func NewTaskRepository(store *Store) *TaskRepository {
+	return &TaskRepository{store: store}
+}
+
+func (r *TaskRepository) Create(ctx context.Context, task *model.Task) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if task.ID.IsZero() {
+		task.ID = primitive.NewObjectID()
+	}
+	r.store.tasks[task.ID] = *task
+
+	return nil
+}
+
+func (r *TaskRepository) FindByID(ctx context.Context, userID, id primitive.ObjectID) (model.Task, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	task, ok := r.store.tasks[id]
+	if !ok || task.UserID != userID {
+		return model.Task{}, model.ErrNotFound
+	}
+
+	return task, nil
+}
+
+func (r *TaskRepository) Update(ctx context.Context, userID, id primitive.ObjectID, update model.UpdateTaskDTO) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	task, ok := r.store.tasks[id]
+	if !ok || task.UserID != userID || task.Status == model.TaskDeleted {
+		return model.ErrNotFound
+	}
+	if update.Status != nil && !task.Status.CanTransitionTo(*update.Status) {
+		return model.ErrInvalidTransition
+	}
+
+	if update.Title != nil {
+		task.Title = *update.Title
+	}
+	if update.Description != nil {
+		task.Description = update.Description
+	}
+	if update.AssignedAt != nil {
+		task.AssignedAt = *update.AssignedAt
+	}
+	if update.Status != nil {
+		task.SetStatus(*update.Status, update.UpdatedAt)
+	}
+	if update.EstimatedPomodoros != nil {
+		task.EstimatedPomodoros = *update.EstimatedPomodoros
+		task.RollupEstimate()
+	}
+	if update.CompletedPomodoros != nil {
+		task.CompletedPomodoros = *update.CompletedPomodoros
+	}
+	if update.Tags != nil {
+		task.Tags = *update.Tags
+	}
+	if update.ProjectID != nil {
+		task.ProjectID = update.ProjectID
+	}
+	if update.Recurrence != nil {
+		task.Recurrence = update.Recurrence
+	}
+	if update.DueAt != nil {
+		task.DueAt = update.DueAt
+	}
+	if update.Priority != nil {
+		task.Priority = *update.Priority
+	}
+	task.UpdatedAt = update.UpdatedAt
+	r.store.tasks[id] = task
+
+	return nil
+}
+
+func (r *TaskRepository) Patch(ctx context.Context, userID, id primitive.ObjectID, patch model.TaskPatch, unmodifiedSince, at time.Time) (model.Task, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	task, ok := r.store.tasks[id]
+	if !ok || task.UserID != userID || task.Status == model.TaskDeleted {
+		return model.Task{}, model.ErrNotFound
+	}
+	if !task.UpdatedAt.Equal(unmodifiedSince) {
+		return model.Task{}, model.ErrConflict
+	}
+	if !task.Status.CanTransitionTo(patch.Status) {
+		return model.Task{}, model.ErrInvalidTransition
+	}
+
+	task.ApplyPatch(patch, at)
+	r.store.tasks[id] = task
+
+	return task, nil
+}
+
+func (r *TaskRepository) Delete(ctx context.Context, userID, id primitive.ObjectID, deletedAt time.Time) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	task, ok := r.store.tasks[id]
+	if !ok || task.UserID != userID || task.Status == model.TaskDeleted {
+		return model.ErrNotFound
+	}
+
+	trash(&task, deletedAt)
+	r.store.tasks[id] = task
+
+	return nil
+}
+
+func (r *TaskRepository) Restore(ctx context.Context, userID, id primitive.ObjectID, at time.Time) (model.Task, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	task, ok := r.store.tasks[id]
+	if !ok || task.UserID != userID || task.Status != model.TaskDeleted {
+		return model.Task{}, model.ErrNotFound
+	}
+
+	restore(&task, at)
+	r.store.tasks[id] = task
+
+	return task, nil
+}
+
+// trash moves task to the trash, remembering its status for restore.
+func trash(task *model.Task, deletedAt time.Time) {
+	task.StatusBeforeDelete = task.Status
+	task.Status = model.TaskDeleted
+	task.DeletedAt = &deletedAt
+}
+
+func restore(task *model.Task, at time.Time) {
+	task.Status = task.StatusBeforeDelete
+	if task.Status == "" {
+		task.Status = model.TaskPending
+	}
+	task.StatusBeforeDelete = ""
+	task.DeletedAt = nil
+	task.UpdatedAt = at
+}
+
+func (r *TaskRepository) Bulk(ctx context.Context, userID primitive.ObjectID, op model.BulkTaskDTO, at time.Time) ([]model.BulkResult, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	results := make([]model.BulkResult, len(op.IDs))
+	for i, id := range op.IDs {
+		results[i].ID = id
+
+		task, ok := r.store.tasks[id]
+		if !ok || task.UserID != userID {
+			results[i].Error = model.ErrNotFound.Error()
+			continue
+		}
+		if err := op.Check(task.Status); err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+
+		switch op.Operation {
+		case model.BulkSetStatus:
+			task.SetStatus(op.Status, at)
+			task.UpdatedAt = at
+		case model.BulkDelete:
+			trash(&task, at)
+		case model.BulkRestore:
+			restore(&task, at)
+		case model.BulkSetTags:
+			task.Tags = slices.Clone(op.Tags)
+			task.UpdatedAt = at
+		case model.BulkReschedule:
+			task.AssignedAt = *op.AssignedAt
+			task.UpdatedAt = at
+		}
+
+		r.store.tasks[id] = task
+		results[i].OK = true
+	}
+
+	return results, nil
+}
+
+func (r *TaskRepository) Purge(ctx context.Context, deletedBefore time.Time, limit int64) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var expired []model.Task
+	for _, task := range r.store.tasks {
+		if task.Status == model.TaskDeleted && task.DeletedAt != nil && task.DeletedAt.Before(deletedBefore) {
+			expired = append(expired, task)
+		}
+	}
+	// Longest in the trash first, like the trashed_tasks index orders them.
+	sort.Slice(expired, func(i, j int) bool { return expired[i].DeletedAt.Before(*expired[j].DeletedAt) })
+	if limit > 0 && limit < int64(len(expired)) {
+		expired = expired[:limit]
+	}
+
+	purged := map[primitive.ObjectID]bool{}
+	for _, task := range expired {
+		purged[task.ID] = true
+		delete(r.store.tasks, task.ID)
+	}
+
+	for id, session := range r.store.sessions {
+		if session.TaskID != nil && purged[*session.TaskID] {
+			delete(r.store.sessions, id)
+		}
+	}
+
+	return int64(len(purged)), nil
+}
+
+func (r *TaskRepository) List(ctx context.Context, f model.TaskFilter) ([]model.Task, int64, error) {
+	tasks, err := r.filter(f)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	switch {
+	case f.SortBy == "" && f.Query != "":
+		sort.SliceStable(tasks, func(i, j int) bool {
+			return textScore(tasks[i], f.Query) > textScore(tasks[j], f.Query)
+		})
+	case f.SortBy == "" || f.SortBy == "created_at" && f.SortDesc:
+	case f.SortBy == "created_at":
+		slices.Reverse(tasks)
+	default:
+		sort.SliceStable(tasks, func(i, j int) bool {
+			c := compareTasks(tasks[i], tasks[j], f.SortBy)
+			if f.SortDesc {
+				return c > 0
+			}
+			return c < 0
+		})
+	}
+
+	return paginate(tasks, f.Skip, f.Limit), int64(len(tasks)), nil
+}
+
+func (r *TaskRepository) ListAfter(ctx context.Context, f model.TaskFilter, after *model.TaskCursor) ([]model.Task, error) {
+	tasks, err := r.filter(f)
+	if err != nil {
+		return nil, err
+	}
+
+	if after != nil {
+		tasks = tasks[sort.Search(len(tasks), func(i int) bool {
+			return taskBefore(*after, model.NewTaskCursor(tasks[i]))
+		}):]
+	}
+
+	return paginate(tasks, 0, f.Limit), nil
+}
+
+// filter returns the tasks matching f in listing order.
+func (r *TaskRepository) filter(f model.TaskFilter) ([]model.Task, error) {
+	var title *regexp.Regexp
+	if f.Title != "" {
+		var err error
+		if title, err = regexp.Compile("(?i)^" + regexp.QuoteMeta(f.Title)); err != nil {
+			return nil, err
+		}
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var tasks []model.Task
+	for _, task := range r.store.tasks {
+		switch {
+		case task.UserID != f.UserID:
+		case f.Status == "" && task.Status == model.TaskDeleted:
+		case f.Status != "" && task.Status != f.Status:
+		case title != nil && !title.MatchString(task.Title):
+		case f.Query != "" && textScore(task, f.Query) == 0:
+		case f.ProjectID != nil && (task.ProjectID == nil || *task.ProjectID != *f.ProjectID):
+		case f.Priority != nil && task.Priority != *f.Priority:
+		case (f.DueAfter != nil || f.DueBefore != nil) && task.DueAt == nil:
+		case f.DueAfter != nil && task.DueAt.Before(*f.DueAfter):
+		case f.DueBefore != nil && task.DueAt.After(*f.DueBefore):
+		case len(f.Tags) > 0 && !hasTags(task.Tags, f.Tags, f.TagsMatchAll):
+		case f.StartDate != nil && task.AssignedAt.Before(*f.StartDate):
+		case f.EndDate != nil && task.AssignedAt.After(*f.EndDate):
+		default:
+			tasks = append(tasks, task)
+		}
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		return taskBefore(model.NewTaskCursor(tasks[i]), model.NewTaskCursor(tasks[j]))
+	})
+
+	return tasks, nil
+}
+
+// taskBefore reports whether a comes before b in listing order, which is
+// created_at and then _id, both descending.
+func taskBefore(a, b model.TaskCursor) bool {
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.After(b.CreatedAt)
+	}
+
+	return bytes.Compare(a.ID[:], b.ID[:]) > 0
+}
+
+func paginate[T any](items []T, skip, limit int64) []T {
+	skip = max(skip, 0)
+	if skip >= int64(len(items)) {
+		return nil
+	}
+	items = items[skip:]
+	if limit > 0 && limit < int64(len(items)) {
+		items = items[:limit]
+	}
+
+	return items
+}
+
+// textScore approximates the relevance ranking of the MongoDB text index by
+// counting the words of the task that equal a search term, weighing a title
+// match above a description match.
+func textScore(task model.Task, query string) int {
+	terms := map[string]bool{}
+	for _, term := range textWords(query) {
+		terms[term] = true
+	}
+
+	score := 0
+	for _, word := range textWords(task.Title) {
+		if terms[word] {
+			score += 3
+		}
+	}
+	if task.Description != nil {
+		for _, word := range textWords(*task.Description) {
+			if terms[word] {
+				score++
+			}
+		}
+	}
+
+	return score
+}
+
+func textWords(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func hasTags(tags, wanted []string, all bool) bool {
+	for _, tag := range wanted {
+		found := slices.Contains(tags, tag)
+		if found && !all {
+			return true
+		}
+		if !found && all {
+			return false
+		}
+	}
+
+	return all
+}
+
+func (r *TaskRepository) Tags(ctx context.Context, userID primitive.ObjectID) ([]model.TagCount, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	counts := map[string]*model.TagCount{}
+	for _, task := range r.store.tasks {
+		if task.UserID != userID || task.Status == model.TaskDeleted {
+			continue
+		}
+		for _, tag := range task.Tags {
+			count, ok := counts[tag]
+			if !ok {
+				count = &model.TagCount{Tag: tag}
+				counts[tag] = count
+			}
+			count.Tasks++
+			if task.Status == model.TaskCompleted {
+				count.CompletedTasks++
+			}
+			count.CompletedPomodoros += int64(task.CompletedPomodoros)
+		}
+	}
+
+	tags := make([]model.TagCount, 0, len(counts))
+	for _, count := range counts {
+		tags = append(tags, *count)
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].Tasks != tags[j].Tasks {
+			return tags[i].Tasks > tags[j].Tasks
+		}
+		return tags[i].Tag < tags[j].Tag
+	})
+
+	return tags, nil
+}
+
+func (r *TaskRepository) RenameTag(ctx context.Context, userID primitive.ObjectID, from, to string, at time.Time) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var modified int64
+	for id, task := range r.store.tasks {
+		if task.UserID != userID || !slices.Contains(task.Tags, from) {
+			continue
+		}
+
+		tags := make([]string, 0, len(task.Tags))
+		for _, tag := range task.Tags {
+			if tag == from {
+				tag = to
+			}
+			if !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+		task.Tags = tags
+		task.UpdatedAt = at
+		r.store.tasks[id] = task
+		modified++
+	}
+
+	return modified, nil
+}
+
+func (r *TaskRepository) AddChecklistItem(ctx context.Context, userID, id primitive.ObjectID, item model.ChecklistItem, at time.Time) (model.Task, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	task, ok := r.store.tasks[id]
+	if !ok || task.UserID != userID || task.Status == model.TaskDeleted {
+		return model.Task{}, model.ErrNotFound
+	}
+
+	task.Checklist = append(slices.Clone(task.Checklist), item)
+	task.RollupEstimate()
+	task.UpdatedAt = at
+	r.store.tasks[id] = task
+
+	return task, nil
+}
+
+func (r *TaskRepository) ReorderChecklist(ctx context.Context, userID, id primitive.ObjectID, itemIDs []primitive.ObjectID, at time.Time) (model.Task, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	task, ok := r.store.tasks[id]
+	if !ok || task.UserID != userID || task.Status == model.TaskDeleted {
+		return model.Task{}, model.ErrNotFound
+	}
+	if !task.IsChecklistOrder(itemIDs) {
+		return model.Task{}, model.ErrConflict
+	}
+
+	items := map[primitive.ObjectID]model.ChecklistItem{}
+	for _, item := range task.Checklist {
+		items[item.ID] = item
+	}
+	task.Checklist = make([]model.ChecklistItem, 0, len(itemIDs))
+	for _, itemID := range itemIDs {
+		task.Checklist = append(task.Checklist, items[itemID])
+	}
+	task.UpdatedAt = at
+	r.store.tasks[id] = task
+
+	return task, nil
+}
+
+func (r *TaskRepository) SetChecklistItemDone(ctx context.Context, userID, id, itemID primitive.ObjectID, done bool, at time.Time) (model.Task, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	task, ok := r.store.tasks[id]
+	if !ok || task.UserID != userID || task.Status == model.TaskDeleted {
+		return model.Task{}, model.ErrNotFound
+	}
+
+	i := slices.IndexFunc(task.Checklist, func(item model.ChecklistItem) bool { return item.ID == itemID })
+	if i < 0 {
+		return model.Task{}, model.ErrNotFound
+	}
+
+	task.Checklist = slices.Clone(task.Checklist)
+	task.Checklist[i].Done = done
+	task.Checklist[i].DoneAt = nil
+	if done {
+		task.Checklist[i].DoneAt = &at
+	}
+	task.UpdatedAt = at
+	r.store.tasks[id] = task
+
+	return task, nil
+}
+
+func (r *TaskRepository) ListRecurring(ctx context.Context, assignedBefore time.Time, after *model.Task, limit int64) ([]model.Task, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	before := func(a, b model.Task) bool {
+		if !a.AssignedAt.Equal(b.AssignedAt) {
+			return a.AssignedAt.Before(b.AssignedAt)
+		}
+		return a.ID.Hex() < b.ID.Hex()
+	}
+
+	var tasks []model.Task
+	for _, task := range r.store.tasks {
+		if task.Recurrence == nil || task.NextCreated || task.Status == model.TaskDeleted {
+			continue
+		}
+		if after != nil && !before(*after, task) {
+			continue
+		}
+		if task.Status == model.TaskCompleted || task.AssignedAt.Before(assignedBefore) {
+			tasks = append(tasks, task)
+		}
+	}
+	sort.Slice(tasks, func(i, j int) bool { return before(tasks[i], tasks[j]) })
+
+	return paginate(tasks, 0, limit), nil
+}
+
+func (r *TaskRepository) CreateNextOccurrence(ctx context.Context, prev model.Task, next *model.Task) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	exists := false
+	for _, task := range r.store.tasks {
+		if task.SeriesID != nil && next.SeriesID != nil && *task.SeriesID == *next.SeriesID && task.AssignedAt.Equal(next.AssignedAt) {
+			exists = true
+			break
+		}
+	}
+	if !exists {
+		if next.ID.IsZero() {
+			next.ID = primitive.NewObjectID()
+		}
+		r.store.tasks[next.ID] = *next
+	}
+
+	if task, ok := r.store.tasks[prev.ID]; ok {
+		task.NextCreated = true
+		r.store.tasks[prev.ID] = task
+	}
+
+	return nil
+}
+
+// compareTasks orders two tasks by field, a missing due date sorting first as
+// it does in MongoDB.
+func compareTasks(a, b model.Task, field string) int {
+	switch field {
+	case "priority":
+		return cmp.Compare(a.Priority, b.Priority)
+	case "due_at":
+		switch {
+		case a.DueAt == nil && b.DueAt == nil:
+			return 0
+		case a.DueAt == nil:
+			return -1
+		case b.DueAt == nil:
+			return 1
+		}
+		return a.DueAt.Compare(*b.DueAt)
+	case "assigned_at":
+		return a.AssignedAt.Compare(b.AssignedAt)
+	case "position":
+		return cmp.Compare(a.Position, b.Position)
+	case "deleted_at":
+		switch {
+		case a.DeletedAt == nil && b.DeletedAt == nil:
+			return 0
+		case a.DeletedAt == nil:
+			return -1
+		case b.DeletedAt == nil:
+			return 1
+		}
+		return a.DeletedAt.Compare(*b.DeletedAt)
+	default:
+		return a.CreatedAt.Compare(b.CreatedAt)
+	}
+}
+
+func (r *TaskRepository) ListForDay(ctx context.Context, userID primitive.ObjectID, start, end time.Time) ([]model.Task, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	inDay := func(t time.Time) bool {
+		return !t.Before(start) && t.Before(end)
+	}
+
+	var tasks []model.Task
+	for _, task := range r.store.tasks {
+		if task.UserID != userID || task.Status == model.TaskDeleted {
+			continue
+		}
+		if inDay(task.AssignedAt) || task.DueAt != nil && inDay(*task.DueAt) {
+			tasks = append(tasks, task)
+		}
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		if c := compareTasks(tasks[i], tasks[j], "priority"); c != 0 {
+			return c > 0
+		}
+		if c := compareTasks(tasks[i], tasks[j], "due_at"); c != 0 {
+			return c < 0
+		}
+		return taskBefore(model.NewTaskCursor(tasks[j]), model.NewTaskCursor(tasks[i]))
+	})
+
+	return tasks, nil
+}
+
+func (r *TaskRepository) Move(ctx context.Context, userID, id primitive.ObjectID, move model.MoveTaskDTO, at time.Time) (model.Task, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	task, ok := r.store.tasks[id]
+	if !ok || task.UserID != userID || task.Status == model.TaskDeleted {
+		return model.Task{}, model.ErrNotFound
+	}
+
+	// The other open tasks of the user in manual order.
+	var others []model.Task
+	for _, other := range r.store.tasks {
+		if other.UserID == userID && other.ID != id && other.Status != model.TaskDeleted {
+			others = append(others, other)
+		}
+	}
+	sortManual(others)
+
+	index := func(anchorID *primitive.ObjectID) (int, error) {
+		if anchorID == nil {
+			return -1, nil
+		}
+		i := slices.IndexFunc(others, func(t model.Task) bool { return t.ID == *anchorID })
+		if i < 0 {
+			return -1, model.ErrNotFound
+		}
+		return i, nil
+	}
+	after, err := index(move.AfterID)
+	if err != nil {
+		return model.Task{}, err
+	}
+	before, err := index(move.BeforeID)
+	if err != nil {
+		return model.Task{}, err
+	}
+	if move.BeforeID == nil {
+		before = after + 1
+	}
+	if move.AfterID == nil {
+		after = before - 1
+	}
+
+	between := func() (int64, bool) {
+		var prev, next *int64
+		if after >= 0 {
+			prev = &others[after].Position
+		}
+		if before < len(others) {
+			next = &others[before].Position
+		}
+		return model.PositionBetween(prev, next)
+	}
+
+	position, ok := between()
+	if !ok {
+		for i := range others {
+			others[i].Position = int64(i+1) * model.PositionGap
+			r.store.tasks[others[i].ID] = others[i]
+		}
+		if position, ok = between(); !ok {
+			return model.Task{}, model.ErrConflict
+		}
+	}
+
+	task.Position = position
+	task.UpdatedAt = at
+	r.store.tasks[id] = task
+
+	return task, nil
+}
+
+// sortManual sorts tasks by position, ties in listing order.
+func sortManual(tasks []model.Task) {
+	sort.Slice(tasks, func(i, j int) bool {
+		if c := compareTasks(tasks[i], tasks[j], "position"); c != 0 {
+			return c < 0
+		}
+		return taskBefore(model.NewTaskCursor(tasks[i]), model.NewTaskCursor(tasks[j]))
+	})
+}
@@ -0,0 +1,102 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/anggara-26/pomodoro-backend.git/app/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type UserRepository struct {
+	store *Store
+}
+
+func NewUserRepository(store *Store) *UserRepository {
+	return &UserRepository{store: store}
+}
+
+func (r *UserRepository) Create(ctx context.Context, user *model.User) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, existing := range r.store.users {
+		if existing.FirebaseUID == user.FirebaseUID {
+			return model.ErrConflict
+		}
+	}
+
+	if user.ID.IsZero() {
+		user.ID = primitive.NewObjectID()
+	}
+	r.store.users[user.ID] = *user
+
+	return nil
+}
+
+func (r *UserRepository) FindByID(ctx context.Context, id primitive.ObjectID) (model.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	user, ok := r.store.users[id]
+	if !ok {
+		return model.User{}, model.ErrNotFound
+	}
+
+	return user, nil
+}
+
+func (r *UserRepository) FindByFirebaseUID(ctx context.Context, firebaseUID string) (model.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, user := range r.store.users {
+		if user.FirebaseUID == firebaseUID {
+			return user, nil
+		}
+	}
+
+	return model.User{}, model.ErrNotFound
+}
+
+func (r *UserRepository) Update(ctx context.Context, id primitive.ObjectID, update model.UpdateUserDTO) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	user, ok := r.store.users[id]
+	if !ok {
+		return model.ErrNotFound
+	}
+	user.Name = update.Name
+	r.store.users[id] = user
+
+	return nil
+}
+
+func (r *UserRepository) Patch(ctx context.Context, id primitive.ObjectID, patch model.UserPatch) (model.User, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	user, ok := r.store.users[id]
+	if !ok {
+		return model.User{}, model.ErrNotFound
+	}
+	user.Name = patch.Name
+	user.Settings = patch.Settings
+	r.store.users[id] = user
+
+	return user, nil
+}
+
+func (r *UserRepository) UpdateSettings(ctx context.Context, id primitive.ObjectID, settings model.Settings) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	user, ok := r.store.users[id]
+	if !ok {
+		return model.ErrNotFound
+	}
+	user.Settings = settings
+	r.store.users[id] = user
+
+	return nil
+}
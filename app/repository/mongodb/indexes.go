@@ -0,0 +1,167 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/anggara-26/pomodoro-backend.git/app/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Weights of the task text index, a match in the title ranks above one in the description.
+const (
+	textWeightTitle       = 3
+	textWeightDescription = 1
+)
+
+// activeSessionIndex keeps a user to a single active session.
+const activeSessionIndex = "one_active_session_per_user"
+
+// EnsureIndexes creates the indexes the repositories rely on. It is safe to
+// call on every boot.
+func EnsureIndexes(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection("users").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "firebase_uid", Value: 1}},
+		Options: options.Index().SetName("one_user_per_firebase_uid").SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	sessions := db.Collection("sessions")
+
+	// Sessions started before the index existed may have left a user with
+	// several active sessions, which would keep the index from being built.
+	if err := demoteDuplicateActive(ctx, sessions); err != nil {
+		return err
+	}
+
+	_, err = sessions.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "user_id", Value: 1}},
+		Options: options.Index().
+			SetName(activeSessionIndex).
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"status": model.SessionActive}),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = sessions.Indexes().CreateOne(ctx, mongo.IndexModel{
+		// Serves the focus time of project rollups.
+		Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "task_id", Value: 1}},
+		Options: options.Index().SetName("user_sessions_by_task"),
+	})
+	if err != nil {
+		return err
+	}
+
+	tasks := db.Collection("tasks")
+
+	// Tasks created before manual ordering have no position and would all sort first.
+	if err := backfillPositions(ctx, tasks); err != nil {
+		return err
+	}
+
+	_, err = tasks.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			// Serves task listings in the order model.TaskCursor points into.
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+			Options: options.Index().SetName("user_tasks_by_created_at"),
+		},
+		{
+			// With user_tasks_by_due_at, serves the day listing.
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "assigned_at", Value: 1}},
+			Options: options.Index().SetName("user_tasks_by_assigned_at"),
+		},
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "due_at", Value: 1}},
+			Options: options.Index().SetName("user_tasks_by_due_at"),
+		},
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "project_id", Value: 1}},
+			Options: options.Index().SetName("user_tasks_by_project"),
+		},
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "tags", Value: 1}},
+			Options: options.Index().SetName("user_tasks_by_tag"),
+		},
+		{
+			Keys: bson.D{{Key: "deleted_at", Value: 1}},
+			Options: options.Index().
+				SetName("trashed_tasks").
+				SetPartialFilterExpression(bson.M{"status": model.TaskDeleted}),
+		},
+		{
+			// Keeps the recurrence generator from creating an occurrence twice.
+			Keys: bson.D{{Key: "series_id", Value: 1}, {Key: "assigned_at", Value: 1}},
+			Options: options.Index().
+				SetName("one_occurrence_per_series_date").
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"series_id": bson.M{"$exists": true}}),
+		},
+		{
+			Keys: bson.D{{Key: "next_created", Value: 1}, {Key: "assigned_at", Value: 1}},
+			Options: options.Index().
+				SetName("recurring_tasks").
+				SetPartialFilterExpression(bson.M{"recurrence": bson.M{"$exists": true}}),
+		},
+		{
+			Keys: bson.D{{Key: "title", Value: "text"}, {Key: "description", Value: "text"}},
+			Options: options.Index().
+				SetName("task_text").
+				SetWeights(bson.M{"title": textWeightTitle, "description": textWeightDescription}),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection("projects").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "name", Value: 1}},
+		Options: options.Index().SetName("user_projects_by_name"),
+	})
+	return err
+}
+
+// demoteDuplicateActive keeps only the latest active session of every user.
+func demoteDuplicateActive(ctx context.Context, sessions *mongo.Collection) error {
+	cursor, err := sessions.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"status": model.SessionActive}}},
+		{{Key: "$sort", Value: bson.D{{Key: "started_at", Value: -1}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$user_id", "ids": bson.M{"$push": "$_id"}}}},
+		{{Key: "$match", Value: bson.M{"ids.1": bson.M{"$exists": true}}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	var groups []struct {
+		IDs bson.A `bson:"ids"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return err
+	}
+
+	for _, group := range groups {
+		_, err := sessions.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": group.IDs[1:]}}, bson.M{"$set": bson.M{"status": model.SessionBreak}})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// backfillPositions gives tasks without a position the one
+// model.InitialPosition assigns on creation.
+func backfillPositions(ctx context.Context, tasks *mongo.Collection) error {
+	createdAt := bson.M{"$ifNull": bson.A{"$created_at", bson.M{"$toDate": "$_id"}}}
+	_, err := tasks.UpdateMany(ctx, bson.M{"position": bson.M{"$exists": false}}, mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{"position": bson.M{"$multiply": bson.A{bson.M{"$toLong": createdAt}, int64(time.Millisecond / time.Microsecond)}}}}},
+	})
+
+	return err
+}
@@ -0,0 +1,16 @@
+// Package mongodb implements the model repositories on top of MongoDB.
+package mongodb
+
+import (
+	"github.com/anggara-26/pomodoro-backend.git/app/model"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func NewModels(db *mongo.Database) model.Models {
+	return model.Models{
+		Tasks:    NewTaskRepository(db),
+		Users:    NewUserRepository(db),
+		Projects: NewProjectRepository(db),
+		Sessions: NewSessionRepository(db),
+	}
+}
@@ -0,0 +1,161 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+
+	"github.com/anggara-26/pomodoro-backend.git/app/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ProjectRepository struct {
+	coll     *mongo.Collection
+	tasks    *mongo.Collection
+	sessions *mongo.Collection
+}
+
+func NewProjectRepository(db *mongo.Database) *ProjectRepository {
+	return &ProjectRepository{
+		coll:     db.Collection("projects"),
+		tasks:    db.Collection("tasks"),
+		sessions: db.Collection("sessions"),
+	}
+}
+
+func (r *ProjectRepository) Create(ctx context.Context, project *model.Project) error {
+	if project.ID.IsZero() {
+		project.ID = primitive.NewObjectID()
+	}
+
+	_, err := r.coll.InsertOne(ctx, project)
+	return err
+}
+
+func (r *ProjectRepository) FindByID(ctx context.Context, userID, id primitive.ObjectID) (model.Project, error) {
+	project := model.Project{}
+	err := r.coll.FindOne(ctx, bson.M{"_id": id, "user_id": userID}).Decode(&project)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return project, model.ErrNotFound
+	}
+
+	return project, err
+}
+
+func (r *ProjectRepository) List(ctx context.Context, userID primitive.ObjectID) ([]model.Project, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}, {Key: "_id", Value: 1}})
+
+	cursor, err := r.coll.Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var projects []model.Project
+	if err := cursor.All(ctx, &projects); err != nil {
+		return nil, err
+	}
+
+	return projects, nil
+}
+
+func (r *ProjectRepository) Update(ctx context.Context, userID, id primitive.ObjectID, update model.UpdateProjectDTO) error {
+	result, err := r.coll.UpdateOne(ctx, bson.M{"_id": id, "user_id": userID}, bson.M{"$set": update})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return model.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *ProjectRepository) Delete(ctx context.Context, userID, id primitive.ObjectID) error {
+	txn, err := r.coll.Database().Client().StartSession()
+	if err != nil {
+		return err
+	}
+	defer txn.EndSession(ctx)
+
+	// Tasks are only detached along with the project, so none is left
+	// pointing at a project that no longer exists.
+	_, err = txn.WithTransaction(ctx, func(ctx mongo.SessionContext) (interface{}, error) {
+		result, err := r.coll.DeleteOne(ctx, bson.M{"_id": id, "user_id": userID})
+		if err != nil {
+			return nil, err
+		}
+		if result.DeletedCount == 0 {
+			return nil, model.ErrNotFound
+		}
+
+		_, err = r.tasks.UpdateMany(ctx, bson.M{"user_id": userID, "project_id": id}, bson.M{"$unset": bson.M{"project_id": ""}})
+		return nil, err
+	})
+
+	return err
+}
+
+func (r *ProjectRepository) Rollup(ctx context.Context, userID, id primitive.ObjectID) (model.ProjectRollup, error) {
+	rollup := model.ProjectRollup{ProjectID: id}
+
+	cursor, err := r.tasks.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"user_id": userID, "project_id": id, "status": bson.M{"$ne": model.TaskDeleted}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   nil,
+			"tasks": bson.M{"$sum": 1},
+			"completed_tasks": bson.M{"$sum": bson.M{
+				"$cond": bson.A{bson.M{"$eq": bson.A{"$status", model.TaskCompleted}}, 1, 0},
+			}},
+			"estimated_pomodoros": bson.M{"$sum": "$estimated_pomodoros"},
+			"completed_pomodoros": bson.M{"$sum": "$completed_pomodoros"},
+		}}},
+	})
+	if err != nil {
+		return rollup, err
+	}
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(&rollup); err != nil {
+			return rollup, err
+		}
+	}
+	if err := cursor.Close(ctx); err != nil {
+		return rollup, err
+	}
+
+	// Focus time counts sessions on every task of the project, deleted ones
+	// included, as that time was spent all the same.
+	taskIDs, err := r.tasks.Distinct(ctx, "_id", bson.M{"user_id": userID, "project_id": id})
+	if err != nil {
+		return rollup, err
+	}
+	if len(taskIDs) == 0 {
+		return rollup, nil
+	}
+
+	cursor, err = r.sessions.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"user_id": userID,
+			"task_id": bson.M{"$in": taskIDs},
+			"type":    model.Focus,
+			"status":  bson.M{"$in": bson.A{model.SessionCompleted, model.SessionSkipped}},
+		}}},
+		{{Key: "$group", Value: bson.M{"_id": nil, "focus_ms": bson.M{"$sum": elapsedMillis}}}},
+	})
+	if err != nil {
+		return rollup, err
+	}
+
+	var focus []struct {
+		FocusMS int64 `bson:"focus_ms"`
+	}
+	if err := cursor.All(ctx, &focus); err != nil {
+		return rollup, err
+	}
+	if len(focus) > 0 {
+		rollup.FocusMinutes = float64(focus[0].FocusMS) / 60000
+	}
+
+	return rollup, nil
+}
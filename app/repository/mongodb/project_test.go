@@ -0,0 +1,99 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/anggara-26/pomodoro-backend.git/app/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestProjectRollup(t *testing.T) {
+	db := newTestDatabase(t)
+	ctx := context.Background()
+	projects := NewProjectRepository(db)
+	tasks := NewTaskRepository(db)
+	userID := primitive.NewObjectID()
+
+	project := model.Project{UserID: userID, Name: "Thesis"}
+	if err := projects.Create(ctx, &project); err != nil {
+		t.Fatal(err)
+	}
+	kept := model.Task{UserID: userID, ProjectID: &project.ID, Title: "Kept", Status: model.TaskCompleted, EstimatedPomodoros: 2, CompletedPomodoros: 1}
+	trashed := model.Task{UserID: userID, ProjectID: &project.ID, Title: "Trashed", Status: model.TaskDeleted, EstimatedPomodoros: 3}
+	loose := model.Task{UserID: userID, Title: "Loose", Status: model.TaskPending}
+	for _, task := range []*model.Task{&kept, &trashed, &loose} {
+		if err := tasks.Create(ctx, task); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	end := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	session := func(taskID primitive.ObjectID, kind model.SessionType, minutes int, status model.SessionStatus) any {
+		return model.Session{ID: primitive.NewObjectID(), UserID: userID, TaskID: &taskID, Type: kind, StartedAt: end.Add(-time.Duration(minutes) * time.Minute), EndedAt: end, Status: status}
+	}
+	_, err := db.Collection("sessions").InsertMany(ctx, []any{
+		session(kept.ID, model.Focus, 25, model.SessionCompleted),
+		session(kept.ID, model.ShortBreak, 5, model.SessionCompleted),
+		session(trashed.ID, model.Focus, 10, model.SessionSkipped),
+		session(loose.ID, model.Focus, 25, model.SessionCompleted),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rollup, err := projects.Rollup(ctx, userID, project.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := model.ProjectRollup{ProjectID: project.ID, Tasks: 1, CompletedTasks: 1, EstimatedPomodoros: 2, CompletedPomodoros: 1, FocusMinutes: 35}
+	if rollup != want {
+		t.Errorf("Rollup() = %+v, want %+v", rollup, want)
+	}
+
+	// A project without tasks has nothing to look up sessions for.
+	empty := model.Project{UserID: userID, Name: "Empty"}
+	if err := projects.Create(ctx, &empty); err != nil {
+		t.Fatal(err)
+	}
+	if rollup, err := projects.Rollup(ctx, userID, empty.ID); err != nil || rollup != (model.ProjectRollup{ProjectID: empty.ID}) {
+		t.Errorf("Rollup() of an empty project = %+v, %v", rollup, err)
+	}
+}
+
+func TestProjectDelete(t *testing.T) {
+	db := newTestDatabase(t)
+	ctx := context.Background()
+	projects := NewProjectRepository(db)
+	tasks := NewTaskRepository(db)
+	userID := primitive.NewObjectID()
+
+	project := model.Project{UserID: userID, Name: "Thesis"}
+	if err := projects.Create(ctx, &project); err != nil {
+		t.Fatal(err)
+	}
+	task := model.Task{UserID: userID, ProjectID: &project.ID, Title: "Task", Status: model.TaskPending}
+	if err := tasks.Create(ctx, &task); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := projects.Delete(ctx, primitive.NewObjectID(), project.ID); !errors.Is(err, model.ErrNotFound) {
+		t.Fatalf("Delete() by another user error = %v, want ErrNotFound", err)
+	}
+	if err := projects.Delete(ctx, userID, project.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := projects.FindByID(ctx, userID, project.ID); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("FindByID() after Delete() error = %v, want ErrNotFound", err)
+	}
+
+	detached, err := tasks.FindByID(ctx, userID, task.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if detached.ProjectID != nil {
+		t.Errorf("project_id = %s, want it unset", detached.ProjectID.Hex())
+	}
+}
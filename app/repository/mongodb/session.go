@@ -0,0 +1,411 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/anggara-26/pomodoro-backend.git/app/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// statsFormats are the $dateToString formats matching model.StatsPeriod.Key.
+var statsFormats = map[model.StatsPeriod]string{
+	model.StatsDaily:   "%Y-%m-%d",
+	model.StatsWeekly:  "%G-W%V",
+	model.StatsMonthly: "%Y-%m",
+}
+
+// elapsedMillis is the time an ended session ran, excluding its pauses, as
+// computed by model.Session.Elapsed.
+var elapsedMillis = bson.M{"$subtract": bson.A{
+	bson.M{"$subtract": bson.A{"$ended_at", "$started_at"}},
+	bson.M{"$sum": bson.M{"$map": bson.M{
+		"input": bson.M{"$ifNull": bson.A{"$pauses", bson.A{}}},
+		"in":    bson.M{"$subtract": bson.A{"$$this.resumed_at", "$$this.paused_at"}},
+	}}},
+}}
+
+type statsRow struct {
+	Period         string            `bson:"period"`
+	Type           model.SessionType `bson:"type"`
+	Completed      int64             `bson:"completed"`
+	Skipped        int64             `bson:"skipped"`
+	FocusMinutes   float64           `bson:"focus_minutes"`
+	TasksCompleted int64             `bson:"tasks_completed"`
+}
+
+type SessionRepository struct {
+	coll *mongo.Collection
+}
+
+func NewSessionRepository(db *mongo.Database) *SessionRepository {
+	return &SessionRepository{coll: db.Collection("sessions")}
+}
+
+const (
+	// startAttempts bounds how often Start retries after losing a race on
+	// the active session index.
+	startAttempts = 3
+	// duplicateKeyCode is the server error code of a unique index violation.
+	duplicateKeyCode = 11000
+)
+
+func (r *SessionRepository) Start(ctx context.Context, session *model.Session) ([]model.Session, error) {
+	if session.ID.IsZero() {
+		session.ID = primitive.NewObjectID()
+	}
+	running := bson.M{"user_id": session.UserID, "status": bson.M{"$in": bson.A{model.SessionActive, model.SessionPaused}}}
+
+	txn, err := r.coll.Database().Client().StartSession()
+	if err != nil {
+		return nil, err
+	}
+	defer txn.EndSession(ctx)
+
+	for attempt := 0; attempt < startAttempts; attempt++ {
+		result, err := txn.WithTransaction(ctx, func(ctx mongo.SessionContext) (interface{}, error) {
+			cursor, err := r.coll.Find(ctx, running)
+			if err != nil {
+				return nil, err
+			}
+			var demoted []model.Session
+			if err := cursor.All(ctx, &demoted); err != nil {
+				return nil, err
+			}
+
+			ids := make(bson.A, len(demoted))
+			for i := range demoted {
+				demoted[i].Status = model.SessionBreak
+				ids[i] = demoted[i].ID
+			}
+			if len(ids) > 0 {
+				_, err := r.coll.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": ids}}, bson.M{"$set": bson.M{"status": model.SessionBreak}})
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			if _, err := r.coll.InsertOne(ctx, session); err != nil {
+				return nil, err
+			}
+
+			return demoted, nil
+		})
+		if err == nil {
+			return result.([]model.Session), nil
+		}
+		if !isActiveSessionConflict(err) {
+			return nil, err
+		}
+	}
+
+	return nil, model.ErrConflict
+}
+
+// isActiveSessionConflict reports whether err is a duplicate key on the
+// active session index, which only another Start racing this one causes.
+func isActiveSessionConflict(err error) bool {
+	var serverErr mongo.ServerError
+	return errors.As(err, &serverErr) && serverErr.HasErrorCodeWithMessage(duplicateKeyCode, activeSessionIndex)
+}
+
+func (r *SessionRepository) FindByID(ctx context.Context, userID, id primitive.ObjectID) (model.Session, error) {
+	session := model.Session{}
+	err := r.coll.FindOne(ctx, bson.M{"_id": id, "user_id": userID}).Decode(&session)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return session, model.ErrNotFound
+	}
+
+	return session, err
+}
+
+func (r *SessionRepository) FindCurrent(ctx context.Context, userID primitive.ObjectID) (model.Session, error) {
+	filter := bson.M{"user_id": userID, "status": bson.M{"$in": bson.A{model.SessionActive, model.SessionPaused}}}
+	// "active" sorts before "paused".
+	opts := options.FindOne().SetSort(bson.D{{Key: "status", Value: 1}, {Key: "started_at", Value: -1}})
+
+	session := model.Session{}
+	err := r.coll.FindOne(ctx, filter, opts).Decode(&session)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return session, model.ErrNotFound
+	}
+
+	return session, err
+}
+
+func (r *SessionRepository) List(ctx context.Context, f model.SessionFilter) ([]model.Session, int64, error) {
+	filter := bson.M{"user_id": f.UserID}
+
+	if f.Type != "" {
+		filter["type"] = f.Type
+	}
+	if f.Status != "" {
+		filter["status"] = f.Status
+	}
+	if f.TaskID != nil {
+		filter["task_id"] = *f.TaskID
+	}
+	if f.StartDate != nil || f.EndDate != nil {
+		startedAt := bson.M{}
+		if f.StartDate != nil {
+			startedAt["$gte"] = *f.StartDate
+		}
+		if f.EndDate != nil {
+			startedAt["$lte"] = *f.EndDate
+		}
+		filter["started_at"] = startedAt
+	}
+
+	order := 1
+	if f.SortDesc {
+		order = -1
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: f.SortBy, Value: order}, {Key: "_id", Value: order}}).
+		SetSkip(f.Skip).
+		SetLimit(f.Limit)
+
+	cursor, err := r.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var sessions []model.Session
+	if err := cursor.All(ctx, &sessions); err != nil {
+		return nil, 0, err
+	}
+
+	total, err := r.coll.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return sessions, total, nil
+}
+
+func (r *SessionRepository) ListEnded(ctx context.Context, userID primitive.ObjectID, limit int64) ([]model.Session, error) {
+	filter := bson.M{"user_id": userID, "status": bson.M{"$in": bson.A{model.SessionCompleted, model.SessionSkipped}}}
+	opts := options.Find().SetSort(bson.D{{Key: "started_at", Value: -1}}).SetLimit(limit)
+
+	cursor, err := r.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var sessions []model.Session
+	if err := cursor.All(ctx, &sessions); err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+func (r *SessionRepository) ListActive(ctx context.Context) ([]model.Session, error) {
+	cursor, err := r.coll.Find(ctx, bson.M{"status": model.SessionActive})
+	if err != nil {
+		return nil, err
+	}
+
+	var sessions []model.Session
+	if err := cursor.All(ctx, &sessions); err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+func (r *SessionRepository) Pause(ctx context.Context, userID, id primitive.ObjectID, at time.Time) (model.Session, error) {
+	filter := bson.M{"_id": id, "user_id": userID, "status": model.SessionActive}
+	update := bson.M{
+		"$set":  bson.M{"status": model.SessionPaused},
+		"$push": bson.M{"pauses": model.Pause{PausedAt: at}},
+	}
+
+	return r.findOneAndUpdate(ctx, filter, update, options.FindOneAndUpdate().SetReturnDocument(options.After))
+}
+
+func (r *SessionRepository) Resume(ctx context.Context, userID, id primitive.ObjectID, at time.Time) (model.Session, error) {
+	filter := bson.M{"_id": id, "user_id": userID, "status": model.SessionPaused}
+	update := bson.M{"$set": bson.M{
+		"status":                    model.SessionActive,
+		"pauses.$[open].resumed_at": at,
+	}}
+	opts := options.FindOneAndUpdate().
+		SetReturnDocument(options.After).
+		SetArrayFilters(options.ArrayFilters{Filters: bson.A{bson.M{"open.resumed_at": bson.M{"$exists": false}}}})
+
+	session, err := r.findOneAndUpdate(ctx, filter, update, opts)
+	if mongo.IsDuplicateKeyError(err) {
+		return session, model.ErrConflict
+	}
+
+	return session, err
+}
+
+func (r *SessionRepository) End(ctx context.Context, userID, id primitive.ObjectID, end model.EndSession) (model.Session, error) {
+	filter := bson.M{"_id": id, "user_id": userID, "status": bson.M{"$in": bson.A{model.SessionActive, model.SessionPaused}}}
+	update := mongo.Pipeline{{{Key: "$set", Value: bson.M{
+		"ended_at": end.EndedAt,
+		"status":   end.Status,
+		"pauses": bson.M{"$map": bson.M{
+			"input": bson.M{"$ifNull": bson.A{"$pauses", bson.A{}}},
+			"in": bson.M{"$cond": bson.A{
+				bson.M{"$eq": bson.A{bson.M{"$ifNull": bson.A{"$$this.resumed_at", nil}}, nil}},
+				bson.M{"$mergeObjects": bson.A{"$$this", bson.M{"resumed_at": end.EndedAt}}},
+				"$$this",
+			}},
+		}},
+	}}}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	txn, err := r.coll.Database().Client().StartSession()
+	if err != nil {
+		return model.Session{}, err
+	}
+	defer txn.EndSession(ctx)
+
+	result, err := txn.WithTransaction(ctx, func(ctx mongo.SessionContext) (interface{}, error) {
+		session, err := r.findOneAndUpdate(ctx, filter, update, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		if session.Type == model.Focus && session.Status == model.SessionCompleted && session.TaskID != nil {
+			if err := r.recordPomodoro(ctx, userID, *session.TaskID, end); err != nil {
+				return nil, err
+			}
+		}
+
+		return session, nil
+	})
+	if err != nil {
+		return model.Session{}, err
+	}
+
+	return result.(model.Session), nil
+}
+
+// recordPomodoro applies model.Task.RecordPomodoro to the task in place.
+func (r *SessionRepository) recordPomodoro(ctx context.Context, userID, taskID primitive.ObjectID, end model.EndSession) error {
+	completed := bson.M{"$add": bson.A{"$completed_pomodoros", 1}}
+	completes := bson.M{"$and": bson.A{
+		end.AutoCompleteTask,
+		bson.M{"$in": bson.A{"$status", bson.A{model.TaskPending, model.TaskInProgress}}},
+		bson.M{"$gte": bson.A{completed, "$estimated_pomodoros"}},
+	}}
+	update := mongo.Pipeline{{{Key: "$set", Value: bson.M{
+		"completed_pomodoros": completed,
+		"status": bson.M{"$switch": bson.M{
+			"branches": bson.A{
+				bson.M{"case": completes, "then": model.TaskCompleted},
+				bson.M{"case": bson.M{"$eq": bson.A{"$status", model.TaskPending}}, "then": model.TaskInProgress},
+			},
+			"default": "$status",
+		}},
+		"completed_at": bson.M{"$cond": bson.A{completes, end.EndedAt, "$completed_at"}},
+		"updated_at":   end.EndedAt,
+	}}}}
+
+	_, err := r.coll.Database().Collection("tasks").UpdateOne(ctx, bson.M{
+		"_id":     taskID,
+		"user_id": userID,
+		"status":  bson.M{"$ne": model.TaskDeleted},
+	}, update)
+	return err
+}
+
+func (r *SessionRepository) findOneAndUpdate(ctx context.Context, filter, update interface{}, opts *options.FindOneAndUpdateOptions) (model.Session, error) {
+	session := model.Session{}
+	err := r.coll.FindOneAndUpdate(ctx, filter, update, opts).Decode(&session)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return session, model.ErrNotFound
+	}
+
+	return session, err
+}
+
+func (r *SessionRepository) Stats(ctx context.Context, q model.StatsQuery) ([]model.StatsBucket, error) {
+	tz := q.Location.String()
+	format := statsFormats[q.Period]
+	sessionBucket := bson.M{"$dateToString": bson.M{"format": format, "date": "$started_at", "timezone": tz}}
+	taskBucket := bson.M{"$dateToString": bson.M{"format": format, "date": "$completed_at", "timezone": tz}}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"user_id":    q.UserID,
+			"started_at": bson.M{"$gte": q.Start, "$lt": q.End},
+			"status":     bson.M{"$in": bson.A{model.SessionCompleted, model.SessionSkipped}},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":       bson.M{"period": sessionBucket, "type": "$type"},
+			"completed": bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$status", model.SessionCompleted}}, 1, 0}}},
+			"skipped":   bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$status", model.SessionSkipped}}, 1, 0}}},
+			"focus_ms": bson.M{"$sum": bson.M{"$cond": bson.A{
+				bson.M{"$eq": bson.A{"$type", model.Focus}},
+				elapsedMillis,
+				0,
+			}}},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":           0,
+			"period":        "$_id.period",
+			"type":          "$_id.type",
+			"completed":     1,
+			"skipped":       1,
+			"focus_minutes": bson.M{"$divide": bson.A{"$focus_ms", 60000}},
+		}}},
+		{{Key: "$unionWith", Value: bson.M{
+			"coll": "tasks",
+			"pipeline": bson.A{
+				bson.M{"$match": bson.M{"user_id": q.UserID, "status": model.TaskCompleted}},
+				// Tasks completed before completed_at was recorded count on their last update,
+				// as model.Task.CompletionTime does.
+				bson.M{"$set": bson.M{"completed_at": bson.M{"$ifNull": bson.A{"$completed_at", "$updated_at"}}}},
+				bson.M{"$match": bson.M{"completed_at": bson.M{"$gte": q.Start, "$lt": q.End}}},
+				bson.M{"$group": bson.M{"_id": taskBucket, "tasks_completed": bson.M{"$sum": 1}}},
+				bson.M{"$project": bson.M{"_id": 0, "period": "$_id", "tasks_completed": 1}},
+			},
+		}}},
+	}
+
+	cursor, err := r.coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []statsRow
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+
+	buckets := map[string]*model.StatsBucket{}
+	for _, row := range rows {
+		b, ok := buckets[row.Period]
+		if !ok {
+			bucket := model.NewStatsBucket(row.Period)
+			b = &bucket
+			buckets[row.Period] = b
+		}
+		b.TasksCompleted += row.TasksCompleted
+		b.FocusMinutes += row.FocusMinutes
+		if row.Type != "" {
+			counts := b.Sessions[row.Type]
+			counts.Completed += row.Completed
+			counts.Skipped += row.Skipped
+			b.Sessions[row.Type] = counts
+		}
+	}
+
+	result := make([]model.StatsBucket, 0, len(buckets))
+	for _, b := range buckets {
+		result = append(result, *b)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Period < result[j].Period })
+
+	return result, nil
+}
@@ -0,0 +1,202 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anggara-26/pomodoro-backend.git/app/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// newTestDatabase returns an empty database on the replica set at
+// MONGODB_TEST_URI, dropped again when the test ends. Start needs
+// transactions, so a standalone server will not do.
+func newTestDatabase(t *testing.T) *mongo.Database {
+	t.Helper()
+
+	uri := os.Getenv("MONGODB_TEST_URI")
+	if uri == "" {
+		t.Skip("MONGODB_TEST_URI is not set")
+	}
+
+	ctx := context.Background()
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := client.Database("pomodoro_test_" + primitive.NewObjectID().Hex())
+	t.Cleanup(func() {
+		db.Drop(ctx)
+		client.Disconnect(ctx)
+	})
+
+	return db
+}
+
+func activeSessions(t *testing.T, coll *mongo.Collection, userID primitive.ObjectID) []model.Session {
+	t.Helper()
+
+	ctx := context.Background()
+	cursor, err := coll.Find(ctx, bson.M{"user_id": userID, "status": model.SessionActive})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sessions []model.Session
+	if err := cursor.All(ctx, &sessions); err != nil {
+		t.Fatal(err)
+	}
+
+	return sessions
+}
+
+func TestStartConcurrently(t *testing.T) {
+	db := newTestDatabase(t)
+	ctx := context.Background()
+	if err := EnsureIndexes(ctx, db); err != nil {
+		t.Fatal(err)
+	}
+	repo := NewSessionRepository(db)
+	userID := primitive.NewObjectID()
+
+	const starts = 20
+	errs := make(chan error, starts)
+	var wg sync.WaitGroup
+	for i := 0; i < starts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := repo.Start(ctx, &model.Session{
+				UserID:    userID,
+				StartedAt: time.Now(),
+				Duration:  25,
+				Type:      model.Focus,
+				Status:    model.SessionActive,
+			})
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil && !errors.Is(err, model.ErrConflict) {
+			t.Errorf("Start() error = %v, want nil or ErrConflict", err)
+		}
+	}
+	if active := activeSessions(t, repo.coll, userID); len(active) != 1 {
+		t.Errorf("active sessions = %d, want 1", len(active))
+	}
+}
+
+func TestStartReturnsDemoted(t *testing.T) {
+	db := newTestDatabase(t)
+	ctx := context.Background()
+	repo := NewSessionRepository(db)
+	userID := primitive.NewObjectID()
+
+	first := model.Session{UserID: userID, StartedAt: time.Now(), Duration: 25, Type: model.Focus, Status: model.SessionActive}
+	if demoted, err := repo.Start(ctx, &first); err != nil || len(demoted) != 0 {
+		t.Fatalf("Start() = %v, %v, want nothing demoted", demoted, err)
+	}
+
+	second := model.Session{UserID: userID, StartedAt: time.Now(), Duration: 25, Type: model.Focus, Status: model.SessionActive}
+	demoted, err := repo.Start(ctx, &second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(demoted) != 1 || demoted[0].ID != first.ID || demoted[0].Status != model.SessionBreak {
+		t.Errorf("Start() demoted %+v, want the first session on a break", demoted)
+	}
+}
+
+func TestStartDuplicateID(t *testing.T) {
+	db := newTestDatabase(t)
+	ctx := context.Background()
+	repo := NewSessionRepository(db)
+
+	session := model.Session{ID: primitive.NewObjectID(), UserID: primitive.NewObjectID(), StartedAt: time.Now(), Type: model.Focus, Status: model.SessionActive}
+	if _, err := repo.Start(ctx, &session); err != nil {
+		t.Fatal(err)
+	}
+
+	// A taken ID is not another session of the user, so it is not a conflict
+	// to retry or report as one.
+	again := session
+	again.UserID = primitive.NewObjectID()
+	if _, err := repo.Start(ctx, &again); !mongo.IsDuplicateKeyError(err) || errors.Is(err, model.ErrConflict) {
+		t.Errorf("Start() error = %v, want the duplicate _id", err)
+	}
+}
+
+func TestIsActiveSessionConflict(t *testing.T) {
+	duplicate := func(message string) error {
+		return mongo.WriteException{WriteErrors: mongo.WriteErrors{{Code: duplicateKeyCode, Message: message}}}
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"active session index", duplicate("E11000 duplicate key error collection: pomodoro.sessions index: one_active_session_per_user dup key: { user_id: ObjectId('65f1c0000000000000000000') }"), true},
+		{"id index", duplicate("E11000 duplicate key error collection: pomodoro.sessions index: _id_ dup key: { _id: ObjectId('65f1c0000000000000000000') }"), false},
+		{"other write error", mongo.WriteException{WriteErrors: mongo.WriteErrors{{Code: 121, Message: activeSessionIndex}}}, false},
+		{"not a server error", errors.New(activeSessionIndex), false},
+		{"nil", nil, false},
+	}
+
+	for _, tt := range tests {
+		if got := isActiveSessionConflict(tt.err); got != tt.want {
+			t.Errorf("%s: isActiveSessionConflict() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestEnsureIndexesDemotesDuplicateActive(t *testing.T) {
+	db := newTestDatabase(t)
+	ctx := context.Background()
+	sessions := db.Collection("sessions")
+
+	userID, otherID := primitive.NewObjectID(), primitive.NewObjectID()
+	start := time.Now().Truncate(time.Millisecond)
+	var docs []interface{}
+	for i := 0; i < 3; i++ {
+		docs = append(docs, model.Session{ID: primitive.NewObjectID(), UserID: userID, StartedAt: start.Add(time.Duration(i) * time.Minute), Type: model.Focus, Status: model.SessionActive})
+	}
+	docs = append(docs, model.Session{ID: primitive.NewObjectID(), UserID: otherID, StartedAt: start, Type: model.Focus, Status: model.SessionActive})
+	if _, err := sessions.InsertMany(ctx, docs); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := EnsureIndexes(ctx, db); err != nil {
+		t.Fatalf("EnsureIndexes() error = %v", err)
+	}
+
+	latest := docs[2].(model.Session)
+	if active := activeSessions(t, sessions, userID); len(active) != 1 || active[0].ID != latest.ID {
+		t.Errorf("active sessions = %+v, want only the latest %s", active, latest.ID.Hex())
+	}
+	if active := activeSessions(t, sessions, otherID); len(active) != 1 {
+		t.Errorf("active sessions of other user = %d, want 1", len(active))
+	}
+	demoted, err := sessions.CountDocuments(ctx, bson.M{"user_id": userID, "status": model.SessionBreak})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if demoted != 2 {
+		t.Errorf("demoted sessions = %d, want 2", demoted)
+	}
+
+	// The index now keeps a second active session out.
+	dup := model.Session{ID: primitive.NewObjectID(), UserID: userID, StartedAt: start, Type: model.Focus, Status: model.SessionActive}
+	if _, err := sessions.InsertOne(ctx, dup); !mongo.IsDuplicateKeyError(err) {
+		t.Errorf("InsertOne() error = %v, want a duplicate key error", err)
+	}
+}
@@ -0,0 +1,807 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/anggara-26/pomodoro-backend.git/app/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type TaskRepository struct {
+	coll     *mongo.Collection
+	sessions *mongo.Collection
+}
+
+func NewTaskRepository(db *mongo.Database) *TaskRepository {
+	return &TaskRepository{
+		coll:     db.Collection("tasks"),
+		sessions: db.Collection("sessions"),
+	}
+}
+
+func (r *TaskRepository) Create(ctx context.Context, task *model.Task) error {
+	if task.ID.IsZero() {
+		task.ID = primitive.NewObjectID()
+	}
+
+	_, err := r.coll.InsertOne(ctx, task)
+	return err
+}
+
+func (r *TaskRepository) FindByID(ctx context.Context, userID, id primitive.ObjectID) (model.Task, error) {
+	task := model.Task{}
+	err := r.coll.FindOne(ctx, bson.M{"_id": id, "user_id": userID}).Decode(&task)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return task, model.ErrNotFound
+	}
+
+	return task, err
+}
+
+func (r *TaskRepository) Update(ctx context.Context, userID, id primitive.ObjectID, update model.UpdateTaskDTO) error {
+	fields, err := literalFields(update)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{"_id": id, "user_id": userID, "status": bson.M{"$ne": model.TaskDeleted}}
+	pipeline := mongo.Pipeline{{{Key: "$set", Value: fields}}}
+	if update.EstimatedPomodoros != nil {
+		pipeline = append(pipeline, rollupEstimate)
+	}
+	if update.Status != nil {
+		filter["status"] = bson.M{"$in": model.TransitionSources(*update.Status)}
+		pipeline = append(mongo.Pipeline{{{Key: "$set", Value: statusFields(*update.Status, update.UpdatedAt)}}}, pipeline...)
+	}
+
+	result, err := r.coll.UpdateOne(ctx, filter, pipeline)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount > 0 {
+		return nil
+	}
+
+	// Nothing matched, either the task is missing or its status is not a source of the transition.
+	if update.Status != nil {
+		count, err := r.coll.CountDocuments(ctx, bson.M{"_id": id, "user_id": userID, "status": bson.M{"$ne": model.TaskDeleted}})
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return model.ErrInvalidTransition
+		}
+	}
+
+	return model.ErrNotFound
+}
+
+func (r *TaskRepository) Patch(ctx context.Context, userID, id primitive.ObjectID, patch model.TaskPatch, unmodifiedSince, at time.Time) (model.Task, error) {
+	fields, err := literalFields(patch)
+	if err != nil {
+		return model.Task{}, err
+	}
+	fields["updated_at"] = at
+
+	filter := bson.M{"_id": id, "user_id": userID, "status": bson.M{"$in": model.TransitionSources(patch.Status)}}
+	if unmodifiedSince.IsZero() {
+		filter["updated_at"] = bson.M{"$exists": false}
+	} else {
+		filter["updated_at"] = unmodifiedSince
+	}
+
+	task, err := r.findOneAndUpdate(ctx, filter, mongo.Pipeline{
+		{{Key: "$set", Value: statusFields(patch.Status, at)}},
+		{{Key: "$set", Value: fields}},
+		rollupEstimate,
+	})
+	if !errors.Is(err, model.ErrNotFound) {
+		return task, err
+	}
+
+	// Nothing matched, find out whether the task is gone, was changed since
+	// it was read or cannot move to the status.
+	current, err := r.FindByID(ctx, userID, id)
+	switch {
+	case err != nil:
+		return model.Task{}, err
+	case current.Status == model.TaskDeleted:
+		return model.Task{}, model.ErrNotFound
+	case !current.UpdatedAt.Equal(unmodifiedSince):
+		return model.Task{}, model.ErrConflict
+	}
+
+	return model.Task{}, model.ErrInvalidTransition
+}
+
+// statusFields sets status, mirroring model.Task.SetStatus, in an update pipeline stage.
+func statusFields(status model.TaskStatus, at time.Time) bson.M {
+	var completedAt any = "$$REMOVE"
+	if status == model.TaskCompleted {
+		completedAt = bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$status", model.TaskCompleted}}, "$completed_at", at}}
+	}
+
+	return bson.M{"status": status, "completed_at": completedAt}
+}
+
+// literalFields marshals doc into $set fields of an update pipeline, wrapping
+// every value in $literal so user input starting with $ is not read as an
+// expression. Null values remove their field.
+func literalFields(doc any) (bson.M, error) {
+	data, err := bson.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields bson.M
+	if err := bson.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for key, value := range fields {
+		if value == nil {
+			fields[key] = "$$REMOVE"
+			continue
+		}
+		fields[key] = bson.M{"$literal": value}
+	}
+
+	return fields, nil
+}
+
+func (r *TaskRepository) Delete(ctx context.Context, userID, id primitive.ObjectID, deletedAt time.Time) error {
+	filter := bson.M{"_id": id, "user_id": userID, "status": bson.M{"$ne": model.TaskDeleted}}
+	result, err := r.coll.UpdateOne(ctx, filter, trashUpdate(deletedAt))
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return model.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *TaskRepository) Restore(ctx context.Context, userID, id primitive.ObjectID, at time.Time) (model.Task, error) {
+	return r.findOneAndUpdate(ctx, bson.M{"_id": id, "user_id": userID, "status": model.TaskDeleted}, restoreUpdate(at))
+}
+
+// trashUpdate moves a task to the trash, remembering its status for restoreUpdate.
+func trashUpdate(deletedAt time.Time) mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"status_before_delete": "$status",
+			"status":               model.TaskDeleted,
+			"deleted_at":           deletedAt,
+		}}},
+	}
+}
+
+func restoreUpdate(at time.Time) mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"status":     bson.M{"$ifNull": bson.A{"$status_before_delete", model.TaskPending}},
+			"updated_at": at,
+		}}},
+		{{Key: "$unset", Value: bson.A{"status_before_delete", "deleted_at"}}},
+	}
+}
+
+func (r *TaskRepository) Bulk(ctx context.Context, userID primitive.ObjectID, op model.BulkTaskDTO, at time.Time) ([]model.BulkResult, error) {
+	var status any = bson.M{"$ne": model.TaskDeleted}
+	var update mongo.Pipeline
+	switch op.Operation {
+	case model.BulkSetStatus:
+		status = bson.M{"$in": model.TransitionSources(op.Status)}
+		fields := statusFields(op.Status, at)
+		fields["updated_at"] = at
+		update = mongo.Pipeline{{{Key: "$set", Value: fields}}}
+	case model.BulkDelete:
+		update = trashUpdate(at)
+	case model.BulkRestore:
+		status = model.TaskDeleted
+		update = restoreUpdate(at)
+	case model.BulkSetTags:
+		update = mongo.Pipeline{{{Key: "$set", Value: bson.M{"tags": bson.M{"$literal": op.Tags}, "updated_at": at}}}}
+	case model.BulkReschedule:
+		update = mongo.Pipeline{{{Key: "$set", Value: bson.M{"assigned_at": op.AssignedAt, "updated_at": at}}}}
+	default:
+		return nil, fmt.Errorf("unknown bulk operation %q", op.Operation)
+	}
+
+	txn, err := r.coll.Database().Client().StartSession()
+	if err != nil {
+		return nil, err
+	}
+	defer txn.EndSession(ctx)
+
+	// The tasks are looked up first, as a bulk write only reports how many of
+	// its operations matched, not which ones. The lookup shares a transaction
+	// with the write, so a task changed in between aborts and retries both
+	// instead of being reported for a write that did not match.
+	results, err := txn.WithTransaction(ctx, func(ctx mongo.SessionContext) (interface{}, error) {
+		cursor, err := r.coll.Find(ctx, bson.M{"_id": bson.M{"$in": op.IDs}, "user_id": userID},
+			options.Find().SetProjection(bson.M{"status": 1}))
+		if err != nil {
+			return nil, err
+		}
+
+		var tasks []struct {
+			ID     primitive.ObjectID `bson:"_id"`
+			Status model.TaskStatus   `bson:"status"`
+		}
+		if err := cursor.All(ctx, &tasks); err != nil {
+			return nil, err
+		}
+
+		statuses := make(map[primitive.ObjectID]model.TaskStatus, len(tasks))
+		for _, task := range tasks {
+			statuses[task.ID] = task.Status
+		}
+
+		results := make([]model.BulkResult, len(op.IDs))
+		writes := make([]mongo.WriteModel, 0, len(tasks))
+		for i, id := range op.IDs {
+			results[i].ID = id
+			current, ok := statuses[id]
+			if !ok {
+				results[i].Error = model.ErrNotFound.Error()
+				continue
+			}
+			if err := op.Check(current); err != nil {
+				results[i].Error = err.Error()
+				continue
+			}
+
+			results[i].OK = true
+			writes = append(writes, mongo.NewUpdateOneModel().
+				SetFilter(bson.M{"_id": id, "user_id": userID, "status": status}).
+				SetUpdate(update))
+		}
+		if len(writes) == 0 {
+			return results, nil
+		}
+
+		result, err := r.coll.BulkWrite(ctx, writes)
+		if err != nil {
+			return nil, err
+		}
+		if result.MatchedCount != int64(len(writes)) {
+			return nil, model.ErrConflict
+		}
+
+		return results, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results.([]model.BulkResult), nil
+}
+
+func (r *TaskRepository) Purge(ctx context.Context, deletedBefore time.Time, limit int64) (int64, error) {
+	filter := bson.M{"status": model.TaskDeleted, "deleted_at": bson.M{"$lt": deletedBefore}}
+	opts := options.Find().
+		SetProjection(bson.M{"_id": 1}).
+		SetSort(bson.D{{Key: "deleted_at", Value: 1}}).
+		SetLimit(limit)
+
+	txn, err := r.coll.Database().Client().StartSession()
+	if err != nil {
+		return 0, err
+	}
+	defer txn.EndSession(ctx)
+
+	// The lookup and both deletes share a transaction, so a task restored
+	// meanwhile either keeps its sessions or is purged along with them.
+	result, err := txn.WithTransaction(ctx, func(ctx mongo.SessionContext) (interface{}, error) {
+		cursor, err := r.coll.Find(ctx, filter, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		var tasks []struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		if err := cursor.All(ctx, &tasks); err != nil {
+			return nil, err
+		}
+		if len(tasks) == 0 {
+			return int64(0), nil
+		}
+
+		ids := make(bson.A, len(tasks))
+		for i, task := range tasks {
+			ids[i] = task.ID
+		}
+
+		if _, err := r.sessions.DeleteMany(ctx, bson.M{"task_id": bson.M{"$in": ids}}); err != nil {
+			return nil, err
+		}
+
+		result, err := r.coll.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}, "status": model.TaskDeleted})
+		if err != nil {
+			return nil, err
+		}
+
+		return result.DeletedCount, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return result.(int64), nil
+}
+
+func (r *TaskRepository) List(ctx context.Context, f model.TaskFilter) ([]model.Task, int64, error) {
+	filter := taskFilter(f)
+	opts := options.Find().SetSort(taskSort(f)).SetSkip(f.Skip).SetLimit(f.Limit)
+
+	cursor, err := r.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var tasks []model.Task
+	if err := cursor.All(ctx, &tasks); err != nil {
+		return nil, 0, err
+	}
+
+	total, err := r.coll.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return tasks, total, nil
+}
+
+func (r *TaskRepository) ListAfter(ctx context.Context, f model.TaskFilter, after *model.TaskCursor) ([]model.Task, error) {
+	filter := taskFilter(f)
+	if after != nil {
+		filter["$or"] = bson.A{
+			bson.M{"created_at": bson.M{"$lt": after.CreatedAt}},
+			bson.M{"created_at": after.CreatedAt, "_id": bson.M{"$lt": after.ID}},
+		}
+	}
+	opts := options.Find().SetSort(taskOrder).SetLimit(f.Limit)
+
+	cursor, err := r.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []model.Task
+	if err := cursor.All(ctx, &tasks); err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// taskOrder is the listing order that model.TaskCursor points into.
+var taskOrder = bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}
+
+// taskSort returns the order of a listing. Without SortBy, search results are
+// ranked by relevance and other listings follow taskOrder.
+func taskSort(f model.TaskFilter) bson.D {
+	switch {
+	case f.SortBy == "" && f.Query != "":
+		return append(bson.D{{Key: "score", Value: bson.M{"$meta": "textScore"}}}, taskOrder...)
+	case f.SortBy == "" || f.SortBy == "created_at" && f.SortDesc:
+		return taskOrder
+	case f.SortBy == "created_at":
+		return bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}
+	}
+
+	direction := 1
+	if f.SortDesc {
+		direction = -1
+	}
+
+	return append(bson.D{{Key: f.SortBy, Value: direction}}, taskOrder...)
+}
+
+func taskFilter(f model.TaskFilter) bson.M {
+	filter := bson.M{"user_id": f.UserID, "status": bson.M{"$ne": string(model.TaskDeleted)}}
+
+	if f.Status != "" {
+		filter["status"] = f.Status
+	}
+	if f.Title != "" {
+		filter["title"] = bson.M{"$regex": "^" + regexp.QuoteMeta(f.Title), "$options": "i"}
+	}
+	if f.Query != "" {
+		filter["$text"] = bson.M{"$search": f.Query}
+	}
+	if f.ProjectID != nil {
+		filter["project_id"] = *f.ProjectID
+	}
+	if f.Priority != nil {
+		filter["priority"] = *f.Priority
+	}
+	if f.DueAfter != nil || f.DueBefore != nil {
+		dueAt := bson.M{}
+		if f.DueAfter != nil {
+			dueAt["$gte"] = *f.DueAfter
+		}
+		if f.DueBefore != nil {
+			dueAt["$lte"] = *f.DueBefore
+		}
+		filter["due_at"] = dueAt
+	}
+	if len(f.Tags) > 0 {
+		op := "$in"
+		if f.TagsMatchAll {
+			op = "$all"
+		}
+		filter["tags"] = bson.M{op: f.Tags}
+	}
+	if f.StartDate != nil || f.EndDate != nil {
+		assignedAt := bson.M{}
+		if f.StartDate != nil {
+			assignedAt["$gte"] = *f.StartDate
+		}
+		if f.EndDate != nil {
+			assignedAt["$lte"] = *f.EndDate
+		}
+		filter["assigned_at"] = assignedAt
+	}
+
+	return filter
+}
+
+func (r *TaskRepository) Tags(ctx context.Context, userID primitive.ObjectID) ([]model.TagCount, error) {
+	cursor, err := r.coll.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"user_id": userID, "status": bson.M{"$ne": model.TaskDeleted}}}},
+		{{Key: "$unwind", Value: "$tags"}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   "$tags",
+			"tasks": bson.M{"$sum": 1},
+			"completed_tasks": bson.M{"$sum": bson.M{
+				"$cond": bson.A{bson.M{"$eq": bson.A{"$status", model.TaskCompleted}}, 1, 0},
+			}},
+			"completed_pomodoros": bson.M{"$sum": "$completed_pomodoros"},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "tasks", Value: -1}, {Key: "_id", Value: 1}}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []model.TagCount
+	if err := cursor.All(ctx, &tags); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+func (r *TaskRepository) RenameTag(ctx context.Context, userID primitive.ObjectID, from, to string, at time.Time) (int64, error) {
+	// Replace from with to, then drop the repeats a merge leaves behind while
+	// keeping the order of the remaining tags.
+	renamed := bson.M{"$map": bson.M{
+		"input": "$tags",
+		"in":    bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$$this", from}}, to, "$$this"}},
+	}}
+	deduplicated := bson.M{"$reduce": bson.M{
+		"input":        renamed,
+		"initialValue": bson.A{},
+		"in": bson.M{"$cond": bson.A{
+			bson.M{"$in": bson.A{"$$this", "$$value"}},
+			"$$value",
+			bson.M{"$concatArrays": bson.A{"$$value", bson.A{"$$this"}}},
+		}},
+	}}
+
+	result, err := r.coll.UpdateMany(ctx, bson.M{"user_id": userID, "tags": from}, mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{"tags": deduplicated, "updated_at": at}}},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return result.ModifiedCount, nil
+}
+
+// rollupEstimate is the update stage matching model.Task.RollupEstimate.
+var rollupEstimate = bson.D{{Key: "$set", Value: bson.M{"estimated_pomodoros": bson.M{"$let": bson.M{
+	"vars": bson.M{"sum": bson.M{"$sum": "$checklist.estimated_pomodoros"}},
+	"in":   bson.M{"$cond": bson.A{bson.M{"$gt": bson.A{"$$sum", 0}}, "$$sum", "$estimated_pomodoros"}},
+}}}}}
+
+func (r *TaskRepository) AddChecklistItem(ctx context.Context, userID, id primitive.ObjectID, item model.ChecklistItem, at time.Time) (model.Task, error) {
+	// The item is wrapped in $literal so a title starting with $ is not read as a field path.
+	return r.findOneAndUpdate(ctx, bson.M{"_id": id, "user_id": userID, "status": bson.M{"$ne": model.TaskDeleted}}, mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"checklist":  bson.M{"$concatArrays": bson.A{bson.M{"$ifNull": bson.A{"$checklist", bson.A{}}}, bson.A{bson.M{"$literal": item}}}},
+			"updated_at": at,
+		}}},
+		rollupEstimate,
+	})
+}
+
+func (r *TaskRepository) ReorderChecklist(ctx context.Context, userID, id primitive.ObjectID, itemIDs []primitive.ObjectID, at time.Time) (model.Task, error) {
+	filter := bson.M{"_id": id, "user_id": userID, "status": bson.M{"$ne": model.TaskDeleted}, "$expr": bson.M{"$and": bson.A{
+		bson.M{"$eq": bson.A{bson.M{"$size": bson.M{"$ifNull": bson.A{"$checklist", bson.A{}}}}, len(itemIDs)}},
+		bson.M{"$setEquals": bson.A{bson.M{"$ifNull": bson.A{"$checklist._id", bson.A{}}}, itemIDs}},
+	}}}
+
+	task, err := r.findOneAndUpdate(ctx, filter, mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"checklist": bson.M{"$map": bson.M{
+				"input": itemIDs,
+				"as":    "id",
+				"in": bson.M{"$arrayElemAt": bson.A{
+					bson.M{"$filter": bson.M{"input": "$checklist", "cond": bson.M{"$eq": bson.A{"$$this._id", "$$id"}}}},
+					0,
+				}},
+			}},
+			"updated_at": at,
+		}}},
+	})
+	if !errors.Is(err, model.ErrNotFound) {
+		return task, err
+	}
+
+	// Tell a missing task apart from a checklist that changed meanwhile.
+	current, err := r.FindByID(ctx, userID, id)
+	if err != nil {
+		return task, err
+	}
+	if current.Status == model.TaskDeleted {
+		return task, model.ErrNotFound
+	}
+
+	return task, model.ErrConflict
+}
+
+func (r *TaskRepository) SetChecklistItemDone(ctx context.Context, userID, id, itemID primitive.ObjectID, done bool, at time.Time) (model.Task, error) {
+	update := bson.M{
+		"$set": bson.M{"checklist.$.done": done, "updated_at": at},
+	}
+	if done {
+		update["$set"].(bson.M)["checklist.$.done_at"] = at
+	} else {
+		update["$unset"] = bson.M{"checklist.$.done_at": ""}
+	}
+
+	return r.findOneAndUpdate(ctx, bson.M{"_id": id, "user_id": userID, "status": bson.M{"$ne": model.TaskDeleted}, "checklist._id": itemID}, update)
+}
+
+func (r *TaskRepository) findOneAndUpdate(ctx context.Context, filter, update interface{}) (model.Task, error) {
+	task := model.Task{}
+	err := r.coll.FindOneAndUpdate(ctx, filter, update, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&task)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return task, model.ErrNotFound
+	}
+
+	return task, err
+}
+
+func (r *TaskRepository) ListRecurring(ctx context.Context, assignedBefore time.Time, after *model.Task, limit int64) ([]model.Task, error) {
+	filter := bson.M{
+		"recurrence": bson.M{"$exists": true},
+		// next_created is only stored once set, matching null serves the rest from the recurring_tasks index.
+		"next_created": nil,
+		"status":       bson.M{"$ne": model.TaskDeleted},
+		"$or": bson.A{
+			bson.M{"status": model.TaskCompleted},
+			bson.M{"assigned_at": bson.M{"$lt": assignedBefore}},
+		},
+	}
+	if after != nil {
+		filter["$and"] = bson.A{bson.M{"$or": bson.A{
+			bson.M{"assigned_at": bson.M{"$gt": after.AssignedAt}},
+			bson.M{"assigned_at": after.AssignedAt, "_id": bson.M{"$gt": after.ID}},
+		}}}
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "assigned_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetLimit(limit)
+
+	cursor, err := r.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []model.Task
+	if err := cursor.All(ctx, &tasks); err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+func (r *TaskRepository) CreateNextOccurrence(ctx context.Context, prev model.Task, next *model.Task) error {
+	if next.ID.IsZero() {
+		next.ID = primitive.NewObjectID()
+	}
+
+	// The unique series index rejects an occurrence another process created
+	// first, which still counts as created.
+	if _, err := r.coll.InsertOne(ctx, next); err != nil && !mongo.IsDuplicateKeyError(err) {
+		return err
+	}
+
+	_, err := r.coll.UpdateOne(ctx, bson.M{"_id": prev.ID}, bson.M{"$set": bson.M{"next_created": true}})
+	return err
+}
+
+func (r *TaskRepository) ListForDay(ctx context.Context, userID primitive.ObjectID, start, end time.Time) ([]model.Task, error) {
+	day := bson.M{"$gte": start, "$lt": end}
+	filter := bson.M{
+		"user_id": userID,
+		"status":  bson.M{"$ne": model.TaskDeleted},
+		"$or":     bson.A{bson.M{"assigned_at": day}, bson.M{"due_at": day}},
+	}
+	opts := options.Find().SetSort(bson.D{
+		{Key: "priority", Value: -1},
+		{Key: "due_at", Value: 1},
+		{Key: "created_at", Value: 1},
+		{Key: "_id", Value: 1},
+	})
+
+	cursor, err := r.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []model.Task
+	if err := cursor.All(ctx, &tasks); err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// manualOrder is the order of taskSort for sorting by position.
+var manualOrder = append(bson.D{{Key: "position", Value: 1}}, taskOrder...)
+
+func (r *TaskRepository) Move(ctx context.Context, userID, id primitive.ObjectID, move model.MoveTaskDTO, at time.Time) (model.Task, error) {
+	live := bson.M{"_id": id, "user_id": userID, "status": bson.M{"$ne": model.TaskDeleted}}
+
+	txn, err := r.coll.Database().Client().StartSession()
+	if err != nil {
+		return model.Task{}, err
+	}
+	defer txn.EndSession(ctx)
+
+	result, err := txn.WithTransaction(ctx, func(ctx mongo.SessionContext) (interface{}, error) {
+		// Moves of one user each bump the same counter, so concurrent moves
+		// conflict and get retried instead of picking the same position.
+		_, err := r.coll.Database().Collection("task_orders").UpdateOne(ctx,
+			bson.M{"_id": userID},
+			bson.M{"$inc": bson.M{"moves": 1}},
+			options.Update().SetUpsert(true),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := r.coll.FindOne(ctx, live).Err(); errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, model.ErrNotFound
+		} else if err != nil {
+			return nil, err
+		}
+
+		prev, next, err := r.neighbors(ctx, userID, id, move)
+		if err != nil {
+			return nil, err
+		}
+
+		position, ok := model.PositionBetween(prev, next)
+		if !ok {
+			if err := r.renumber(ctx, userID); err != nil {
+				return nil, err
+			}
+			if prev, next, err = r.neighbors(ctx, userID, id, move); err != nil {
+				return nil, err
+			}
+			if position, ok = model.PositionBetween(prev, next); !ok {
+				return nil, model.ErrConflict
+			}
+		}
+
+		return r.findOneAndUpdate(ctx, live, bson.M{"$set": bson.M{"position": position, "updated_at": at}})
+	})
+	if err != nil {
+		return model.Task{}, err
+	}
+
+	return result.(model.Task), nil
+}
+
+// neighbors returns the positions a moved task goes between, nil at an end of
+// the list. A task sharing the position of an anchor counts as its neighbor,
+// so ties leave no room and get renumbered.
+func (r *TaskRepository) neighbors(ctx context.Context, userID, id primitive.ObjectID, move model.MoveTaskDTO) (prev, next *int64, err error) {
+	anchor := func(anchorID primitive.ObjectID) (*int64, error) {
+		task, err := r.FindByID(ctx, userID, anchorID)
+		if err != nil {
+			return nil, err
+		}
+		if task.Status == model.TaskDeleted {
+			return nil, model.ErrNotFound
+		}
+		return &task.Position, nil
+	}
+	// adjacent returns the closest position to the anchor in direction.
+	adjacent := func(anchorID primitive.ObjectID, position int64, direction int) (*int64, error) {
+		op := "$gte"
+		if direction < 0 {
+			op = "$lte"
+		}
+		filter := bson.M{
+			"user_id":  userID,
+			"_id":      bson.M{"$nin": bson.A{id, anchorID}},
+			"status":   bson.M{"$ne": model.TaskDeleted},
+			"position": bson.M{op: position},
+		}
+		task := model.Task{}
+		err := r.coll.FindOne(ctx, filter, options.FindOne().SetSort(bson.D{{Key: "position", Value: direction}})).Decode(&task)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &task.Position, nil
+	}
+
+	if move.AfterID != nil {
+		if prev, err = anchor(*move.AfterID); err != nil {
+			return nil, nil, err
+		}
+	}
+	if move.BeforeID != nil {
+		if next, err = anchor(*move.BeforeID); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	switch {
+	case move.AfterID != nil && move.BeforeID == nil:
+		next, err = adjacent(*move.AfterID, *prev, 1)
+	case move.BeforeID != nil && move.AfterID == nil:
+		prev, err = adjacent(*move.BeforeID, *next, -1)
+	}
+
+	return prev, next, err
+}
+
+// renumber spreads the non-deleted tasks of the user PositionGap apart,
+// keeping their manual order.
+func (r *TaskRepository) renumber(ctx context.Context, userID primitive.ObjectID) error {
+	opts := options.Find().SetSort(manualOrder).SetProjection(bson.M{"_id": 1})
+
+	cursor, err := r.coll.Find(ctx, bson.M{"user_id": userID, "status": bson.M{"$ne": model.TaskDeleted}}, opts)
+	if err != nil {
+		return err
+	}
+
+	var tasks []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &tasks); err != nil {
+		return err
+	}
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	writes := make([]mongo.WriteModel, len(tasks))
+	for i, task := range tasks {
+		writes[i] = mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": task.ID}).
+			SetUpdate(bson.M{"$set": bson.M{"position": int64(i+1) * model.PositionGap}})
+	}
+
+	_, err = r.coll.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	return err
+}
@@ -0,0 +1,250 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anggara-26/pomodoro-backend.git/app/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestMoveConcurrently(t *testing.T) {
+	db := newTestDatabase(t)
+	ctx := context.Background()
+	repo := NewTaskRepository(db)
+	userID := primitive.NewObjectID()
+
+	const moves = 10
+	tasks := make([]model.Task, moves+1)
+	for i := range tasks {
+		tasks[i] = model.Task{UserID: userID, Title: "Task", Status: model.TaskPending, Position: int64(i+1) * model.PositionGap}
+		if err := repo.Create(ctx, &tasks[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Every move lands right after the first task, so without serializing
+	// them several would pick the same position.
+	var wg sync.WaitGroup
+	for _, task := range tasks[1:] {
+		wg.Add(1)
+		go func(id primitive.ObjectID) {
+			defer wg.Done()
+			if _, err := repo.Move(ctx, userID, id, model.MoveTaskDTO{AfterID: &tasks[0].ID}, time.Now()); err != nil {
+				t.Errorf("Move() error = %v", err)
+			}
+		}(task.ID)
+	}
+	wg.Wait()
+
+	listed, _, err := repo.List(ctx, model.TaskFilter{UserID: userID, Limit: moves + 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := map[int64]bool{}
+	for _, task := range listed {
+		if seen[task.Position] {
+			t.Errorf("position %d is taken twice", task.Position)
+		}
+		seen[task.Position] = true
+	}
+}
+
+func TestMoveTrashedTask(t *testing.T) {
+	db := newTestDatabase(t)
+	ctx := context.Background()
+	repo := NewTaskRepository(db)
+	userID := primitive.NewObjectID()
+
+	a := model.Task{UserID: userID, Title: "a", Status: model.TaskPending, Position: model.PositionGap}
+	b := model.Task{UserID: userID, Title: "b", Status: model.TaskPending, Position: 2 * model.PositionGap}
+	for _, task := range []*model.Task{&a, &b} {
+		if err := repo.Create(ctx, task); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := repo.Delete(ctx, userID, a.ID, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := repo.Move(ctx, userID, a.ID, model.MoveTaskDTO{AfterID: &b.ID}, time.Now()); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("Move() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestEnsureIndexesBackfillsPositions(t *testing.T) {
+	db := newTestDatabase(t)
+	ctx := context.Background()
+	tasks := db.Collection("tasks")
+
+	createdAt := time.Date(2026, 1, 2, 3, 4, 5, 6000000, time.UTC)
+	legacy := primitive.NewObjectIDFromTimestamp(createdAt)
+	_, err := tasks.InsertMany(ctx, []interface{}{
+		bson.M{"_id": primitive.NewObjectID(), "user_id": primitive.NewObjectID(), "title": "Created", "status": model.TaskPending, "created_at": createdAt},
+		bson.M{"_id": legacy, "user_id": primitive.NewObjectID(), "title": "Undated", "status": model.TaskPending},
+		bson.M{"_id": primitive.NewObjectID(), "user_id": primitive.NewObjectID(), "title": "Placed", "status": model.TaskPending, "created_at": createdAt, "position": int64(7)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := EnsureIndexes(ctx, db); err != nil {
+		t.Fatalf("EnsureIndexes() error = %v", err)
+	}
+
+	want := map[string]int64{
+		"Created": model.InitialPosition(createdAt),
+		"Undated": model.InitialPosition(legacy.Timestamp()),
+		"Placed":  7,
+	}
+	cursor, err := tasks.Find(ctx, bson.M{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []model.Task
+	if err := cursor.All(ctx, &got); err != nil {
+		t.Fatal(err)
+	}
+	for _, task := range got {
+		if task.Position != want[task.Title] {
+			t.Errorf("position of %s = %d, want %d", task.Title, task.Position, want[task.Title])
+		}
+	}
+}
+
+func TestListAfterBreaksTiesOnID(t *testing.T) {
+	db := newTestDatabase(t)
+	ctx := context.Background()
+	repo := NewTaskRepository(db)
+	userID := primitive.NewObjectID()
+
+	createdAt := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	want := map[primitive.ObjectID]bool{}
+	for i := 0; i < 5; i++ {
+		task := model.Task{UserID: userID, Title: "Task", Status: model.TaskPending, CreatedAt: createdAt}
+		if err := repo.Create(ctx, &task); err != nil {
+			t.Fatal(err)
+		}
+		want[task.ID] = true
+	}
+
+	var after *model.TaskCursor
+	for len(want) > 0 {
+		page, err := repo.ListAfter(ctx, model.TaskFilter{UserID: userID, Limit: 2}, after)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(page) == 0 {
+			t.Fatalf("ran out of tasks, %d never listed", len(want))
+		}
+		for _, task := range page {
+			if !want[task.ID] {
+				t.Fatalf("task %s listed twice", task.ID.Hex())
+			}
+			delete(want, task.ID)
+		}
+		cursor := model.NewTaskCursor(page[len(page)-1])
+		after = &cursor
+	}
+}
+
+func TestPurge(t *testing.T) {
+	db := newTestDatabase(t)
+	ctx := context.Background()
+	repo := NewTaskRepository(db)
+	sessions := NewSessionRepository(db)
+	userID := primitive.NewObjectID()
+	now := time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC)
+
+	// The first two are trashed longest ago, the last one is live.
+	deletedAt := []time.Time{now.Add(-60 * 24 * time.Hour), now.Add(-45 * 24 * time.Hour), now.Add(-40 * 24 * time.Hour), now.Add(-time.Hour), {}}
+	tasks := make([]model.Task, len(deletedAt))
+	for i := range tasks {
+		tasks[i] = model.Task{UserID: userID, Title: "Task", Status: model.TaskPending}
+		if err := repo.Create(ctx, &tasks[i]); err != nil {
+			t.Fatal(err)
+		}
+		if !deletedAt[i].IsZero() {
+			if err := repo.Delete(ctx, userID, tasks[i].ID, deletedAt[i]); err != nil {
+				t.Fatal(err)
+			}
+		}
+		session := model.Session{UserID: userID, TaskID: &tasks[i].ID, StartedAt: now, Type: model.Focus, Status: model.SessionCompleted}
+		if _, err := sessions.coll.InsertOne(ctx, session); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	purged, err := repo.Purge(ctx, now.Add(-30*24*time.Hour), 2)
+	if err != nil || purged != 2 {
+		t.Fatalf("Purge() = %d, %v, want 2", purged, err)
+	}
+
+	for i, task := range tasks {
+		gone := i < 2
+		count, err := repo.coll.CountDocuments(ctx, bson.M{"_id": task.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		left, err := sessions.coll.CountDocuments(ctx, bson.M{"task_id": task.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if gone != (count == 0) || gone != (left == 0) {
+			t.Errorf("task %d: %d documents and %d sessions left, want purged %v", i, count, left, gone)
+		}
+	}
+}
+
+func TestListRecurringPages(t *testing.T) {
+	db := newTestDatabase(t)
+	ctx := context.Background()
+	repo := NewTaskRepository(db)
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	daily := &model.Recurrence{Frequency: model.RecurDaily}
+
+	// Three tasks share an assigned time so pages must break ties on _id.
+	var want []primitive.ObjectID
+	for i := range 5 {
+		task := model.Task{UserID: primitive.NewObjectID(), Title: "Task", Status: model.TaskPending, Recurrence: daily, AssignedAt: now.Add(-time.Duration(min(i, 2)) * time.Hour)}
+		if err := repo.Create(ctx, &task); err != nil {
+			t.Fatal(err)
+		}
+		want = append(want, task.ID)
+	}
+	created := model.Task{UserID: primitive.NewObjectID(), Title: "Created", Status: model.TaskPending, Recurrence: daily, AssignedAt: now.Add(-time.Hour), NextCreated: true}
+	if err := repo.Create(ctx, &created); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []primitive.ObjectID
+	var after *model.Task
+	for {
+		tasks, err := repo.ListRecurring(ctx, now, after, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, task := range tasks {
+			got = append(got, task.ID)
+		}
+		if len(tasks) < 2 {
+			break
+		}
+		after = &tasks[len(tasks)-1]
+	}
+
+	// The task assigned at now is neither before it nor completed.
+	want = []primitive.ObjectID{want[2], want[3], want[4], want[1]}
+	if len(got) != len(want) {
+		t.Fatalf("ListRecurring() pages = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("ListRecurring() pages = %v, want %v", got, want)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+
+	"github.com/anggara-26/pomodoro-backend.git/app/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type UserRepository struct {
+	coll *mongo.Collection
+}
+
+func NewUserRepository(db *mongo.Database) *UserRepository {
+	return &UserRepository{coll: db.Collection("users")}
+}
+
+func (r *UserRepository) Create(ctx context.Context, user *model.User) error {
+	if user.ID.IsZero() {
+		user.ID = primitive.NewObjectID()
+	}
+
+	_, err := r.coll.InsertOne(ctx, user)
+	if mongo.IsDuplicateKeyError(err) {
+		return model.ErrConflict
+	}
+
+	return err
+}
+
+func (r *UserRepository) FindByID(ctx context.Context, id primitive.ObjectID) (model.User, error) {
+	return r.findOne(ctx, bson.M{"_id": id})
+}
+
+func (r *UserRepository) FindByFirebaseUID(ctx context.Context, firebaseUID string) (model.User, error) {
+	return r.findOne(ctx, bson.M{"firebase_uid": firebaseUID})
+}
+
+func (r *UserRepository) Update(ctx context.Context, id primitive.ObjectID, update model.UpdateUserDTO) error {
+	result, err := r.coll.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": update})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return model.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *UserRepository) Patch(ctx context.Context, id primitive.ObjectID, patch model.UserPatch) (model.User, error) {
+	user := model.User{}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err := r.coll.FindOneAndUpdate(ctx, bson.M{"_id": id}, bson.M{"$set": patch}, opts).Decode(&user)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return user, model.ErrNotFound
+	}
+
+	return user, err
+}
+
+func (r *UserRepository) UpdateSettings(ctx context.Context, id primitive.ObjectID, settings model.Settings) error {
+	result, err := r.coll.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"settings": settings}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return model.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *UserRepository) findOne(ctx context.Context, filter bson.M) (model.User, error) {
+	user := model.User{}
+	err := r.coll.FindOne(ctx, filter).Decode(&user)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return user, model.ErrNotFound
+	}
+
+	return user, err
+}
@@ -0,0 +1,60 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/anggara-26/pomodoro-backend.git/app/model"
+)
+
+const (
+	// purgeInterval is how often the trash is checked for expired tasks.
+	purgeInterval = time.Hour
+	// purgeBatch bounds how many tasks are hard-deleted per repository call.
+	purgeBatch = 500
+)
+
+// Purger hard-deletes tasks, along with their sessions, once they have been
+// in the trash for longer than the retention period.
+type Purger struct {
+	models    model.Models
+	retention time.Duration
+}
+
+func NewPurger(models model.Models, retention time.Duration) *Purger {
+	return &Purger{models: models, retention: retention}
+}
+
+// Run purges expired tasks until ctx is done.
+func (p *Purger) Run(ctx context.Context) {
+	ticker := time.NewTicker(purgeInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := p.purge(ctx, time.Now().UTC().Add(-p.retention)); err != nil {
+			log.Printf("scheduler: failed to purge deleted tasks: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *Purger) purge(ctx context.Context, deletedBefore time.Time) error {
+	for {
+		purged, err := p.models.Tasks.Purge(ctx, deletedBefore, purgeBatch)
+		if err != nil {
+			return err
+		}
+		if purged > 0 {
+			log.Printf("scheduler: purged %d deleted tasks", purged)
+		}
+		if purged < purgeBatch {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/anggara-26/pomodoro-backend.git/app/model"
+	"github.com/anggara-26/pomodoro-backend.git/app/repository/memory"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestPurge(t *testing.T) {
+	ctx := context.Background()
+	models := memory.NewModels()
+	userID := primitive.NewObjectID()
+	now := time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC)
+	retention := 30 * 24 * time.Hour
+
+	// Tasks by how long ago they were trashed, zero for live tasks.
+	ages := map[string]time.Duration{
+		"oldest":   60 * 24 * time.Hour,
+		"expired":  31 * 24 * time.Hour,
+		"recent":   29 * 24 * time.Hour,
+		"live":     0,
+		"restored": 45 * 24 * time.Hour,
+	}
+	ids := map[string]primitive.ObjectID{}
+	for title, age := range ages {
+		task := model.Task{UserID: userID, Title: title, Status: model.TaskPending}
+		if err := models.Tasks.Create(ctx, &task); err != nil {
+			t.Fatal(err)
+		}
+		ids[title] = task.ID
+		if age > 0 {
+			if err := models.Tasks.Delete(ctx, userID, task.ID, now.Add(-age)); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		taskID := task.ID
+		session := model.Session{UserID: userID, TaskID: &taskID, StartedAt: now.Add(-90 * 24 * time.Hour), Type: model.Focus, Status: model.SessionActive}
+		if _, err := models.Sessions.Start(ctx, &session); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := models.Tasks.Restore(ctx, userID, ids["restored"], now); err != nil {
+		t.Fatal(err)
+	}
+
+	// A batch of one takes the task that was trashed longest.
+	purged, err := models.Tasks.Purge(ctx, now.Add(-retention), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := models.Tasks.FindByID(ctx, userID, ids["oldest"]); purged != 1 || !errors.Is(err, model.ErrNotFound) {
+		t.Fatalf("Purge() = %d and the oldest task is %v, want it purged alone", purged, err)
+	}
+
+	p := NewPurger(models, retention)
+	if err := p.purge(ctx, now.Add(-retention)); err != nil {
+		t.Fatal(err)
+	}
+
+	for title, gone := range map[string]bool{"oldest": true, "expired": true, "recent": false, "live": false, "restored": false} {
+		taskID := ids[title]
+		_, err := models.Tasks.FindByID(ctx, userID, taskID)
+		if gone != errors.Is(err, model.ErrNotFound) {
+			t.Errorf("task %s: FindByID() error = %v, want purged %v", title, err, gone)
+		}
+
+		sessions, _, err := models.Sessions.List(ctx, model.SessionFilter{UserID: userID, TaskID: &taskID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if gone != (len(sessions) == 0) {
+			t.Errorf("task %s: %d sessions left, want purged %v", title, len(sessions), gone)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/anggara-26/pomodoro-backend.git/app/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// generateInterval is how often recurring tasks are checked for a due occurrence.
+	generateInterval = time.Minute
+	// generateBatch bounds how many recurring tasks are loaded per repository call.
+	generateBatch = 500
+)
+
+// Generator creates the next occurrence of recurring tasks once they are
+// completed or their day has passed in the time zone of their user. Several
+// processes may run it at once, the repository drops duplicate occurrences.
+type Generator struct {
+	models model.Models
+}
+
+func NewGenerator(models model.Models) *Generator {
+	return &Generator{models: models}
+}
+
+// Run generates due occurrences until ctx is done.
+func (g *Generator) Run(ctx context.Context) {
+	ticker := time.NewTicker(generateInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := g.generate(ctx, time.Now().UTC()); err != nil {
+			log.Printf("scheduler: failed to generate recurring tasks: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (g *Generator) generate(ctx context.Context, now time.Time) error {
+	locations := map[primitive.ObjectID]*time.Location{}
+
+	var after *model.Task
+	for {
+		tasks, err := g.models.Tasks.ListRecurring(ctx, now, after, generateBatch)
+		if err != nil {
+			return err
+		}
+
+		// One failing task must not hold back the series of everyone else.
+		for _, task := range tasks {
+			if err := g.generateNext(ctx, task, now, locations); err != nil {
+				log.Printf("scheduler: failed to generate the next occurrence of task %s: %v", task.ID.Hex(), err)
+			}
+		}
+
+		if len(tasks) < generateBatch {
+			return nil
+		}
+		after = &tasks[len(tasks)-1]
+	}
+}
+
+// generateNext creates the next occurrence of task if it is due, caching the
+// time zone of its user in locations.
+func (g *Generator) generateNext(ctx context.Context, task model.Task, now time.Time, locations map[primitive.ObjectID]*time.Location) error {
+	loc, ok := locations[task.UserID]
+	if !ok {
+		user, err := g.models.Users.FindByID(ctx, task.UserID)
+		if err != nil && !errors.Is(err, model.ErrNotFound) {
+			return err
+		}
+		loc = user.Settings.Location()
+		locations[task.UserID] = loc
+	}
+
+	if !task.IsDue(now, loc) {
+		return nil
+	}
+
+	next, ok := task.NextOccurrence(now, loc)
+	if !ok {
+		return nil
+	}
+
+	return g.models.Tasks.CreateNextOccurrence(ctx, task, &next)
+}
@@ -0,0 +1,67 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/anggara-26/pomodoro-backend.git/app/model"
+	"github.com/anggara-26/pomodoro-backend.git/app/repository/memory"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestGenerate(t *testing.T) {
+	ctx := context.Background()
+	models := memory.NewModels()
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	daily := &model.Recurrence{Frequency: model.RecurDaily}
+
+	// More due tasks than fit in one batch, for users without settings.
+	due := generateBatch + 1
+	for i := range due {
+		task := model.Task{
+			UserID:     primitive.NewObjectID(),
+			Title:      "due",
+			AssignedAt: time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC).Add(time.Duration(i%3) * time.Minute),
+			Status:     model.TaskPending,
+			Recurrence: daily,
+		}
+		if err := models.Tasks.Create(ctx, &task); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Still March 2 in Jakarta, so not due yet.
+	user := model.User{FirebaseUID: "jakarta", Settings: model.Settings{Timezone: "Asia/Jakarta"}}
+	if err := models.Users.Create(ctx, &user); err != nil {
+		t.Fatal(err)
+	}
+	waiting := model.Task{UserID: user.ID, Title: "waiting", AssignedAt: time.Date(2026, 3, 1, 20, 0, 0, 0, time.UTC), Status: model.TaskPending, Recurrence: daily}
+	if err := models.Tasks.Create(ctx, &waiting); err != nil {
+		t.Fatal(err)
+	}
+
+	for run := range 2 {
+		if err := NewGenerator(models).generate(ctx, now); err != nil {
+			t.Fatal(err)
+		}
+
+		// Only the waiting task and the new occurrences are left without a next one.
+		tasks, err := models.Tasks.ListRecurring(ctx, now.Add(24*time.Hour), nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(tasks) != due+1 {
+			t.Fatalf("run %d: %d tasks without a next occurrence, want %d", run, len(tasks), due+1)
+		}
+		for _, task := range tasks {
+			switch {
+			case task.ID == waiting.ID:
+			case task.SeriesID == nil:
+				t.Fatalf("run %d: task %s has no next occurrence", run, task.ID.Hex())
+			case task.AssignedAt.YearDay() != now.YearDay():
+				t.Fatalf("run %d: occurrence assigned at %s, want March 2", run, task.AssignedAt)
+			}
+		}
+	}
+}
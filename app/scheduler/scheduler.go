@@ -0,0 +1,182 @@
+// Package scheduler runs the background jobs of the API process.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/anggara-26/pomodoro-backend.git/app/model"
+	"github.com/anggara-26/pomodoro-backend.git/app/realtime"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// rescanInterval is how often active sessions are reloaded, which picks up
+	// sessions started by other API processes.
+	rescanInterval = time.Minute
+	// retryInterval is how long a failed completion waits before it is retried.
+	retryInterval = 30 * time.Second
+)
+
+// Scheduler completes active sessions once their duration has elapsed, so a
+// session ends on time even if no client calls /sessions/end.
+type Scheduler struct {
+	models model.Models
+	hub    realtime.Hub
+
+	mu     sync.Mutex
+	ctx    context.Context
+	timers map[primitive.ObjectID]*timer
+}
+
+type timer struct {
+	*time.Timer
+}
+
+func New(models model.Models, hub realtime.Hub) *Scheduler {
+	return &Scheduler{
+		models: models,
+		hub:    hub,
+		ctx:    context.Background(),
+		timers: map[primitive.ObjectID]*timer{},
+	}
+}
+
+// Run schedules every active session and keeps rescanning them until ctx is done.
+func (s *Scheduler) Run(ctx context.Context) {
+	s.mu.Lock()
+	s.ctx = ctx
+	s.mu.Unlock()
+
+	ticker := time.NewTicker(rescanInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.rescan(ctx); err != nil {
+			log.Printf("scheduler: failed to load active sessions: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			s.stop()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Schedule completes session at its deadline, replacing any earlier timer for it.
+func (s *Scheduler) Schedule(session model.Session) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.schedule(session.UserID, session.ID, time.Until(session.Deadline()))
+}
+
+// Cancel forgets the timer of a session that ended some other way.
+func (s *Scheduler) Cancel(id primitive.ObjectID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if t, ok := s.timers[id]; ok {
+		t.Stop()
+		delete(s.timers, id)
+	}
+}
+
+func (s *Scheduler) schedule(userID, id primitive.ObjectID, after time.Duration) {
+	if t, ok := s.timers[id]; ok {
+		t.Stop()
+	}
+
+	t := &timer{}
+	t.Timer = time.AfterFunc(after, func() { s.complete(userID, id, t) })
+	s.timers[id] = t
+}
+
+func (s *Scheduler) rescan(ctx context.Context) error {
+	sessions, err := s.models.Sessions.ListActive(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, session := range sessions {
+		if _, ok := s.timers[session.ID]; !ok {
+			s.schedule(session.UserID, session.ID, time.Until(session.Deadline()))
+		}
+	}
+
+	return nil
+}
+
+// complete ends the session if it is still active and past its deadline. The
+// session is reloaded first, as it may have been changed since it was scheduled.
+func (s *Scheduler) complete(userID, id primitive.ObjectID, t *timer) {
+	s.mu.Lock()
+	ctx := s.ctx
+	current := s.timers[id] == t
+	s.mu.Unlock()
+
+	if !current || ctx.Err() != nil {
+		return
+	}
+
+	session, err := s.models.Sessions.FindByID(ctx, userID, id)
+	if err == nil && session.Status == model.SessionActive {
+		if remaining := time.Until(session.Deadline()); remaining > 0 {
+			s.reschedule(userID, id, t, remaining)
+			return
+		}
+
+		var user model.User
+		user, err = s.models.Users.FindByID(ctx, userID)
+		if err == nil {
+			session, err = s.models.Sessions.End(ctx, userID, id, model.EndSession{
+				EndedAt:          session.Deadline(),
+				Status:           model.SessionCompleted,
+				AutoCompleteTask: user.Settings.AutoCompleteTasks,
+			})
+		}
+		if err == nil {
+			s.hub.Publish(userID, realtime.NewEvent(realtime.EventEnd, session, time.Now().UTC()))
+		}
+	}
+	if err != nil && !errors.Is(err, model.ErrNotFound) {
+		log.Printf("scheduler: failed to complete session %s: %v", id.Hex(), err)
+
+		s.reschedule(userID, id, t, retryInterval)
+		return
+	}
+
+	s.mu.Lock()
+	if s.timers[id] == t {
+		delete(s.timers, id)
+	}
+	s.mu.Unlock()
+}
+
+// reschedule runs complete again later, unless t has been replaced meanwhile.
+func (s *Scheduler) reschedule(userID, id primitive.ObjectID, t *timer, after time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.timers[id] == t {
+		s.schedule(userID, id, after)
+	}
+}
+
+func (s *Scheduler) stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, t := range s.timers {
+		t.Stop()
+		delete(s.timers, id)
+	}
+}
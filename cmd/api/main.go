@@ -4,10 +4,19 @@ import (
 	"context"
 	"log"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
 	"time"
 
+	"github.com/anggara-26/pomodoro-backend.git/app/handler"
 	"github.com/anggara-26/pomodoro-backend.git/app/model"
+	"github.com/anggara-26/pomodoro-backend.git/app/realtime"
+	"github.com/anggara-26/pomodoro-backend.git/app/repository/memory"
+	"github.com/anggara-26/pomodoro-backend.git/app/repository/mongodb"
+	"github.com/anggara-26/pomodoro-backend.git/app/scheduler"
 	"github.com/anggara-26/pomodoro-backend.git/db"
+	"github.com/anggara-26/pomodoro-backend.git/pkg/auth"
 	"github.com/anggara-26/pomodoro-backend.git/pkg/router"
 	"github.com/gofiber/fiber/v2"
 )
@@ -19,26 +28,92 @@ type Application struct {
 // @title           Pomodoro API
 // @version         1.0
 // @description     This is the API for Pomodoro App
+//
+// @securityDefinitions.apikey BearerAuth
+// @in              header
+// @name            Authorization
+// @description     Firebase ID token, prefixed with "Bearer "
 func main() {
-	mongoClient, err := db.ConnectToMongo()
-	if err != nil {
-		log.Panic(err)
-	}
+	var application Application
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+	// STORAGE=memory runs the API without MongoDB, data is lost on exit.
+	if os.Getenv("STORAGE") == "memory" {
+		log.Println("Using in-memory storage")
 
-	defer func() {
-		if err = mongoClient.Disconnect(ctx); err != nil {
-			panic(err)
+		application.Models = memory.NewModels()
+	} else {
+		mongoClient, err := db.ConnectToMongo()
+		if err != nil {
+			log.Panic(err)
 		}
-	}()
 
-	log.Println("Connected to MongoDB!")
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			if err = mongoClient.Disconnect(ctx); err != nil {
+				panic(err)
+			}
+		}()
+
+		log.Println("Connected to MongoDB!")
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		if err := mongodb.EnsureIndexes(ctx, db.GetDB()); err != nil {
+			log.Panic(err)
+		}
+
+		application.Models = mongodb.NewModels(db.GetDB())
+	}
 
 	log.Println("Server is running on port " + os.Getenv("PORT"))
 
+	projectID := os.Getenv("FIREBASE_PROJECT_ID")
+	if projectID == "" {
+		log.Panic("FIREBASE_PROJECT_ID is not set")
+	}
+
+	var keys auth.KeySource = auth.NewRemoteKeySource(auth.GoogleJWKSURL)
+	if path := os.Getenv("FIREBASE_JWKS_FILE"); path != "" {
+		keys = auth.NewFileKeySource(path)
+	}
+
+	// ctx is done on SIGINT or SIGTERM, which stops the schedulers and shuts the server down.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Active sessions are rescheduled on boot, so a restart does not leave them running forever.
+	hub := realtime.NewLocalHub()
+	sessionScheduler := scheduler.New(application.Models, hub)
+	go sessionScheduler.Run(ctx)
+	go scheduler.NewGenerator(application.Models).Run(ctx)
+
+	// TRASH_RETENTION_DAYS is how long deleted tasks stay in the trash, 30 days by default.
+	retentionDays := 30
+	if days := os.Getenv("TRASH_RETENTION_DAYS"); days != "" {
+		var err error
+		if retentionDays, err = strconv.Atoi(days); err != nil || retentionDays < 1 {
+			log.Panic("TRASH_RETENTION_DAYS must be a positive number of days")
+		}
+	}
+	go scheduler.NewPurger(application.Models, time.Duration(retentionDays)*24*time.Hour).Run(ctx)
+
+	// Event stream tickets are issued and redeemed by this process, like the hub's events.
+	tickets := auth.NewTickets(30 * time.Second)
+
 	app := fiber.New()
-	router.CreateRouter(app)
-	app.Listen(":" + os.Getenv("PORT"))
+	router.CreateRouter(app, handler.New(ctx, application.Models, sessionScheduler, hub, tickets), auth.NewVerifier(projectID, keys), tickets, application.Models.Users)
+
+	go func() {
+		<-ctx.Done()
+		if err := app.ShutdownWithTimeout(10 * time.Second); err != nil {
+			log.Printf("Failed to shut down: %v", err)
+		}
+	}()
+
+	if err := app.Listen(":" + os.Getenv("PORT")); err != nil {
+		log.Panic(err)
+	}
 }
@@ -11,8 +11,8 @@ import (
 
 var db *mongo.Database
 
-func GetDBCollection(col string) *mongo.Collection {
-	return db.Collection(col)
+func GetDB() *mongo.Database {
+	return db
 }
 
 func ConnectToMongo() (*mongo.Client, error) {
@@ -0,0 +1,162 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// GoogleJWKSURL serves the public keys used to sign Firebase ID tokens.
+const GoogleJWKSURL = "https://www.googleapis.com/service_accounts/v1/jwk/securetoken@system.gserviceaccount.com"
+
+// KeySource provides the RSA public keys used to verify token signatures, keyed by "kid".
+type KeySource interface {
+	Keys(ctx context.Context) (map[string]*rsa.PublicKey, error)
+}
+
+type jwks struct {
+	Keys []struct {
+		Kid string `json:"kid"`
+		Kty string `json:"kty"`
+		Alg string `json:"alg"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+	} `json:"keys"`
+}
+
+// ParseJWKS parses a JSON Web Key Set and returns its RSA keys.
+func ParseJWKS(data []byte) (map[string]*rsa.PublicKey, error) {
+	var set jwks
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("parse jwks: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" || k.Kid == "" {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("parse jwks key %s: %w", k.Kid, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("parse jwks key %s: %w", k.Kid, err)
+		}
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("parse jwks: no RSA keys found")
+	}
+
+	return keys, nil
+}
+
+// FileKeySource reads keys from a local JWKS file. It is meant for tests and local development.
+type FileKeySource struct {
+	path string
+
+	once sync.Once
+	keys map[string]*rsa.PublicKey
+	err  error
+}
+
+func NewFileKeySource(path string) *FileKeySource {
+	return &FileKeySource{path: path}
+}
+
+func (s *FileKeySource) Keys(ctx context.Context) (map[string]*rsa.PublicKey, error) {
+	s.once.Do(func() {
+		data, err := os.ReadFile(s.path)
+		if err != nil {
+			s.err = err
+			return
+		}
+		s.keys, s.err = ParseJWKS(data)
+	})
+
+	return s.keys, s.err
+}
+
+// RemoteKeySource fetches keys over HTTP and caches them for as long as the
+// response's Cache-Control max-age allows.
+type RemoteKeySource struct {
+	url    string
+	client *http.Client
+
+	mu      sync.Mutex
+	keys    map[string]*rsa.PublicKey
+	expires time.Time
+}
+
+func NewRemoteKeySource(url string) *RemoteKeySource {
+	return &RemoteKeySource{
+		url:    url,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (s *RemoteKeySource) Keys(ctx context.Context) (map[string]*rsa.PublicKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.keys != nil && time.Now().Before(s.expires) {
+		return s.keys, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch jwks: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch jwks: unexpected status %d", res.StatusCode)
+	}
+
+	var raw json.RawMessage
+	if err := json.NewDecoder(res.Body).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("fetch jwks: %w", err)
+	}
+	keys, err := ParseJWKS(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	s.keys = keys
+	s.expires = time.Now().Add(maxAge(res.Header.Get("Cache-Control")))
+
+	return s.keys, nil
+}
+
+// maxAge reads max-age from a Cache-Control header, falling back to one hour.
+func maxAge(header string) time.Duration {
+	for _, directive := range strings.Split(header, ",") {
+		directive = strings.TrimSpace(directive)
+		if v, ok := strings.CutPrefix(directive, "max-age="); ok {
+			if seconds, err := strconv.Atoi(v); err == nil && seconds > 0 {
+				return time.Duration(seconds) * time.Second
+			}
+		}
+	}
+
+	return time.Hour
+}
@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Ticket stands in for an ID token where a client cannot send headers, such
+// as EventSource. It is redeemed once and expires quickly, so unlike a token
+// it is harmless once it shows up in a URL or an access log.
+type Ticket struct {
+	Ticket    string    `json:"ticket"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type ticketGrant struct {
+	userID    primitive.ObjectID
+	expiresAt time.Time
+}
+
+// Tickets issues and redeems tickets. Like realtime.LocalHub it only knows the
+// tickets of the current process.
+type Tickets struct {
+	mu     sync.Mutex
+	ttl    time.Duration
+	grants map[string]ticketGrant
+}
+
+func NewTickets(ttl time.Duration) *Tickets {
+	return &Tickets{ttl: ttl, grants: map[string]ticketGrant{}}
+}
+
+// Issue returns a new ticket for userID.
+func (t *Tickets) Issue(userID primitive.ObjectID, now time.Time) (Ticket, error) {
+	value := make([]byte, 32)
+	if _, err := rand.Read(value); err != nil {
+		return Ticket{}, err
+	}
+	ticket := Ticket{Ticket: base64.RawURLEncoding.EncodeToString(value), ExpiresAt: now.Add(t.ttl)}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// Tickets that were never redeemed are dropped here rather than by a timer.
+	for value, grant := range t.grants {
+		if !now.Before(grant.expiresAt) {
+			delete(t.grants, value)
+		}
+	}
+	t.grants[ticket.Ticket] = ticketGrant{userID: userID, expiresAt: ticket.ExpiresAt}
+
+	return ticket, nil
+}
+
+// Redeem returns the user a ticket was issued to and invalidates it. It
+// reports false if the ticket is unknown, already redeemed or expired.
+func (t *Tickets) Redeem(value string, now time.Time) (primitive.ObjectID, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	grant, ok := t.grants[value]
+	if !ok {
+		return primitive.NilObjectID, false
+	}
+	delete(t.grants, value)
+
+	return grant.userID, now.Before(grant.expiresAt)
+}
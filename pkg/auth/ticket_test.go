@@ -0,0 +1,51 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestTickets(t *testing.T) {
+	tickets := NewTickets(30 * time.Second)
+	userID := primitive.NewObjectID()
+
+	ticket, err := tickets.Issue(userID, testNow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ticket.ExpiresAt.Equal(testNow.Add(30 * time.Second)) {
+		t.Errorf("ExpiresAt = %s, want 30s after issue", ticket.ExpiresAt)
+	}
+
+	if got, ok := tickets.Redeem(ticket.Ticket, testNow.Add(10*time.Second)); !ok || got != userID {
+		t.Fatalf("Redeem() = %s, %v, want %s, true", got.Hex(), ok, userID.Hex())
+	}
+	if _, ok := tickets.Redeem(ticket.Ticket, testNow.Add(10*time.Second)); ok {
+		t.Error("Redeem() of a redeemed ticket succeeded")
+	}
+
+	expired, err := tickets.Issue(userID, testNow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := tickets.Redeem(expired.Ticket, testNow.Add(30*time.Second)); ok {
+		t.Error("Redeem() of an expired ticket succeeded")
+	}
+	if _, ok := tickets.Redeem("unknown", testNow); ok {
+		t.Error("Redeem() of an unknown ticket succeeded")
+	}
+
+	// Issuing drops tickets that expired without being redeemed.
+	stale, err := tickets.Issue(userID, testNow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tickets.Issue(userID, testNow.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := tickets.grants[stale.Ticket]; ok || len(tickets.grants) != 1 {
+		t.Errorf("%d tickets kept, want only the fresh one", len(tickets.grants))
+	}
+}
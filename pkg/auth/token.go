@@ -0,0 +1,142 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// clockSkew is how far the token timestamps may drift from the server clock.
+const clockSkew = 5 * time.Minute
+
+var ErrInvalidToken = errors.New("invalid token")
+
+// Token holds the verified claims of a Firebase ID token.
+type Token struct {
+	UID           string
+	Email         string
+	EmailVerified bool
+	IssuedAt      time.Time
+	ExpiresAt     time.Time
+	AuthTime      time.Time
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+type claims struct {
+	Iss           string `json:"iss"`
+	Aud           string `json:"aud"`
+	Sub           string `json:"sub"`
+	Iat           int64  `json:"iat"`
+	Exp           int64  `json:"exp"`
+	AuthTime      int64  `json:"auth_time"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+}
+
+// Verifier checks Firebase ID tokens issued for a single Firebase project.
+type Verifier struct {
+	projectID string
+	keys      KeySource
+	now       func() time.Time
+}
+
+func NewVerifier(projectID string, keys KeySource) *Verifier {
+	return &Verifier{
+		projectID: projectID,
+		keys:      keys,
+		now:       time.Now,
+	}
+}
+
+// Verify validates the signature and claims of raw and returns the decoded token.
+// See https://firebase.google.com/docs/auth/admin/verify-id-tokens for the rules applied.
+func (v *Verifier) Verify(ctx context.Context, raw string) (*Token, error) {
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed token", ErrInvalidToken)
+	}
+
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	if h.Alg != "RS256" {
+		return nil, fmt.Errorf("%w: unexpected algorithm %q", ErrInvalidToken, h.Alg)
+	}
+
+	keys, err := v.keys.Keys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := keys[h.Kid]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown key id %q", ErrInvalidToken, h.Kid)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return nil, fmt.Errorf("%w: bad signature", ErrInvalidToken)
+	}
+
+	var cl claims
+	if err := decodeSegment(parts[1], &cl); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	if err := v.validate(cl); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	return &Token{
+		UID:           cl.Sub,
+		Email:         cl.Email,
+		EmailVerified: cl.EmailVerified,
+		IssuedAt:      time.Unix(cl.Iat, 0).UTC(),
+		ExpiresAt:     time.Unix(cl.Exp, 0).UTC(),
+		AuthTime:      time.Unix(cl.AuthTime, 0).UTC(),
+	}, nil
+}
+
+func (v *Verifier) validate(cl claims) error {
+	now := v.now()
+
+	switch {
+	case cl.Aud != v.projectID:
+		return fmt.Errorf("unexpected audience %q", cl.Aud)
+	case cl.Iss != "https://securetoken.google.com/"+v.projectID:
+		return fmt.Errorf("unexpected issuer %q", cl.Iss)
+	case cl.Sub == "" || len(cl.Sub) > 128:
+		return errors.New("invalid subject")
+	case now.Add(-clockSkew).After(time.Unix(cl.Exp, 0)):
+		return errors.New("token has expired")
+	case now.Add(clockSkew).Before(time.Unix(cl.Iat, 0)):
+		return errors.New("token issued in the future")
+	case now.Add(clockSkew).Before(time.Unix(cl.AuthTime, 0)):
+		return errors.New("token authenticated in the future")
+	}
+
+	return nil
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
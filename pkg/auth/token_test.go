@@ -0,0 +1,235 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testProjectID = "pomodoro-test"
+
+var testNow = time.Date(2026, time.October, 18, 12, 0, 0, 0, time.UTC)
+
+// newTestVerifier returns a verifier trusting key under kid "test-key",
+// read from a JWKS file the way local development does.
+func newTestVerifier(t *testing.T, key *rsa.PrivateKey) *Verifier {
+	t.Helper()
+
+	set := map[string]any{"keys": []map[string]string{{
+		"kid": "test-key",
+		"kty": "RSA",
+		"alg": "RS256",
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}}}
+	data, err := json.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	v := NewVerifier(testProjectID, NewFileKeySource(path))
+	v.now = func() time.Time { return testNow }
+
+	return v
+}
+
+func validClaims() claims {
+	return claims{
+		Iss:           "https://securetoken.google.com/" + testProjectID,
+		Aud:           testProjectID,
+		Sub:           "user-1",
+		Iat:           testNow.Add(-time.Minute).Unix(),
+		Exp:           testNow.Add(time.Hour).Unix(),
+		AuthTime:      testNow.Add(-time.Minute).Unix(),
+		Email:         "user@example.com",
+		EmailVerified: true,
+	}
+}
+
+func sign(t *testing.T, key *rsa.PrivateKey, h header, cl claims) string {
+	t.Helper()
+
+	encode := func(v any) string {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+
+	signed := encode(h) + "." + encode(cl)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestVerify(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := newTestVerifier(t, key)
+
+	valid := header{Alg: "RS256", Kid: "test-key"}
+	tests := []struct {
+		name    string
+		token   func() string
+		wantErr string
+	}{
+		{
+			name:  "valid",
+			token: func() string { return sign(t, key, valid, validClaims()) },
+		},
+		{
+			name:    "malformed",
+			token:   func() string { return "not-a-token" },
+			wantErr: "malformed token",
+		},
+		{
+			name:    "bad signature",
+			token:   func() string { return sign(t, otherKey, valid, validClaims()) },
+			wantErr: "bad signature",
+		},
+		{
+			name: "tampered claims",
+			token: func() string {
+				parts := strings.Split(sign(t, key, valid, validClaims()), ".")
+				cl := validClaims()
+				cl.Sub = "someone-else"
+				data, _ := json.Marshal(cl)
+				parts[1] = base64.RawURLEncoding.EncodeToString(data)
+				return strings.Join(parts, ".")
+			},
+			wantErr: "bad signature",
+		},
+		{
+			name: "wrong audience",
+			token: func() string {
+				cl := validClaims()
+				cl.Aud = "another-project"
+				return sign(t, key, valid, cl)
+			},
+			wantErr: "unexpected audience",
+		},
+		{
+			name: "wrong issuer",
+			token: func() string {
+				cl := validClaims()
+				cl.Iss = "https://securetoken.google.com/another-project"
+				return sign(t, key, valid, cl)
+			},
+			wantErr: "unexpected issuer",
+		},
+		{
+			name: "missing subject",
+			token: func() string {
+				cl := validClaims()
+				cl.Sub = ""
+				return sign(t, key, valid, cl)
+			},
+			wantErr: "invalid subject",
+		},
+		{
+			name: "expired",
+			token: func() string {
+				cl := validClaims()
+				cl.Exp = testNow.Add(-clockSkew - time.Second).Unix()
+				return sign(t, key, valid, cl)
+			},
+			wantErr: "token has expired",
+		},
+		{
+			name: "expired within clock skew",
+			token: func() string {
+				cl := validClaims()
+				cl.Exp = testNow.Add(-time.Minute).Unix()
+				return sign(t, key, valid, cl)
+			},
+		},
+		{
+			name: "issued in the future",
+			token: func() string {
+				cl := validClaims()
+				cl.Iat = testNow.Add(clockSkew + time.Minute).Unix()
+				return sign(t, key, valid, cl)
+			},
+			wantErr: "token issued in the future",
+		},
+		{
+			name: "authenticated in the future",
+			token: func() string {
+				cl := validClaims()
+				cl.AuthTime = testNow.Add(clockSkew + time.Minute).Unix()
+				return sign(t, key, valid, cl)
+			},
+			wantErr: "token authenticated in the future",
+		},
+		{
+			name: "unknown key id",
+			token: func() string {
+				return sign(t, key, header{Alg: "RS256", Kid: "rotated-away"}, validClaims())
+			},
+			wantErr: "unknown key id",
+		},
+		{
+			name: "none algorithm",
+			token: func() string {
+				parts := strings.Split(sign(t, key, header{Alg: "none", Kid: "test-key"}, validClaims()), ".")
+				return parts[0] + "." + parts[1] + "."
+			},
+			wantErr: "unexpected algorithm",
+		},
+		{
+			name:    "HMAC algorithm",
+			token:   func() string { return sign(t, key, header{Alg: "HS256", Kid: "test-key"}, validClaims()) },
+			wantErr: "unexpected algorithm",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := v.Verify(context.Background(), tt.token())
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Verify() error = %v, want nil", err)
+				}
+				if token.UID != "user-1" || token.Email != "user@example.com" || !token.EmailVerified {
+					t.Errorf("Verify() = %+v, want the claims of user-1", token)
+				}
+				return
+			}
+
+			if !errors.Is(err, ErrInvalidToken) {
+				t.Fatalf("Verify() error = %v, want ErrInvalidToken", err)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Verify() error = %q, want it to mention %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+// Package mergepatch applies JSON merge patches as defined by RFC 7396.
+package mergepatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var ErrInvalidPatch = errors.New("invalid merge patch")
+
+// Apply merges patch into the JSON document doc: objects are merged
+// recursively, null removes a member and any other value replaces it.
+func Apply(doc, patch []byte) ([]byte, error) {
+	p, err := decode(patch)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+
+	d, err := decode(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(merge(d, p))
+}
+
+// ApplyTo merges patch into the JSON encoding of v and decodes the result
+// back into a zero T, so members the patch removed end up as zero values.
+// Members T has no field for are rejected with ErrInvalidPatch.
+func ApplyTo[T any](v *T, patch []byte) error {
+	doc, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	merged, err := Apply(doc, patch)
+	if err != nil {
+		return err
+	}
+
+	var result T
+	dec := json.NewDecoder(bytes.NewReader(merged))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&result); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+	*v = result
+
+	return nil
+}
+
+func decode(data []byte) (any, error) {
+	var v any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	// Numbers are kept verbatim, so large integers survive the round trip.
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, errors.New("unexpected data after the document")
+	}
+
+	return v, nil
+}
+
+func merge(target, patch any) any {
+	patchObject, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]any)
+	if !ok {
+		targetObject = map[string]any{}
+	}
+	for name, value := range patchObject {
+		if value == nil {
+			delete(targetObject, name)
+			continue
+		}
+		targetObject[name] = merge(targetObject[name], value)
+	}
+
+	return targetObject
+}
@@ -1,7 +1,133 @@
 package middleware
 
-import "github.com/gofiber/fiber/v2"
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
 
-func AuthMiddleware(c *fiber.Ctx) error {
-	return c.Next()
+	"github.com/anggara-26/pomodoro-backend.git/app/model"
+	"github.com/anggara-26/pomodoro-backend.git/pkg/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Keys used to store the authenticated caller in c.Locals.
+const (
+	TokenKey = "token"
+	UserKey  = "user"
+)
+
+var errMissingToken = errors.New("missing bearer token")
+
+// AuthMiddleware verifies the Firebase ID token in the Authorization header and
+// stores the matching model.User in c.Locals(UserKey).
+func AuthMiddleware(v *auth.Verifier, users model.UserRepository) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		token, err := verify(c, v)
+		if err != nil {
+			return unauthorized(c, err)
+		}
+
+		user, err := users.FindByFirebaseUID(c.Context(), token.UID)
+		if errors.Is(err, model.ErrNotFound) {
+			return c.Status(http.StatusForbidden).JSON(fiber.Map{
+				"message": "User is not registered",
+				"code":    http.StatusForbidden,
+			})
+		}
+		if err != nil {
+			return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+				"message": "Failed to get user",
+				"code":    http.StatusInternalServerError,
+			})
+		}
+
+		c.Locals(TokenKey, token)
+		c.Locals(UserKey, user)
+
+		return c.Next()
+	}
+}
+
+// TokenMiddleware only verifies the Firebase ID token and stores it in
+// c.Locals(TokenKey). It is used where the caller may not be registered yet.
+func TokenMiddleware(v *auth.Verifier) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		token, err := verify(c, v)
+		if err != nil {
+			return unauthorized(c, err)
+		}
+
+		c.Locals(TokenKey, token)
+
+		return c.Next()
+	}
+}
+
+// TicketMiddleware redeems the auth.Ticket in the ticket query parameter, for
+// clients such as EventSource that cannot set headers, and stores its
+// model.User in c.Locals(UserKey). Requests without a ticket are left to
+// authenticate, usually AuthMiddleware.
+func TicketMiddleware(tickets *auth.Tickets, users model.UserRepository, authenticate fiber.Handler) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ticket := c.Query("ticket")
+		if ticket == "" {
+			return authenticate(c)
+		}
+
+		userID, ok := tickets.Redeem(ticket, time.Now())
+		if !ok {
+			return c.Status(http.StatusUnauthorized).JSON(fiber.Map{
+				"message": "Invalid ticket",
+				"code":    http.StatusUnauthorized,
+			})
+		}
+
+		user, err := users.FindByID(c.Context(), userID)
+		if errors.Is(err, model.ErrNotFound) {
+			return c.Status(http.StatusForbidden).JSON(fiber.Map{
+				"message": "User is not registered",
+				"code":    http.StatusForbidden,
+			})
+		}
+		if err != nil {
+			return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+				"message": "Failed to get user",
+				"code":    http.StatusInternalServerError,
+			})
+		}
+
+		c.Locals(UserKey, user)
+
+		return c.Next()
+	}
+}
+
+func verify(c *fiber.Ctx, v *auth.Verifier) (*auth.Token, error) {
+	raw, ok := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+	if !ok || raw == "" {
+		return nil, errMissingToken
+	}
+
+	return v.Verify(c.Context(), raw)
+}
+
+func unauthorized(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, errMissingToken):
+		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{
+			"message": "Missing bearer token",
+			"code":    http.StatusUnauthorized,
+		})
+	case errors.Is(err, auth.ErrInvalidToken):
+		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{
+			"message": "Invalid token",
+			"code":    http.StatusUnauthorized,
+		})
+	default:
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+			"message": "Failed to verify token",
+			"code":    http.StatusInternalServerError,
+		})
+	}
 }
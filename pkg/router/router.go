@@ -2,7 +2,10 @@ package router
 
 import (
 	"github.com/anggara-26/pomodoro-backend.git/app/handler"
+	"github.com/anggara-26/pomodoro-backend.git/app/model"
 	_ "github.com/anggara-26/pomodoro-backend.git/docs/v1"
+	"github.com/anggara-26/pomodoro-backend.git/pkg/auth"
+	"github.com/anggara-26/pomodoro-backend.git/pkg/middleware"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/logger"
@@ -10,7 +13,7 @@ import (
 	"github.com/gofiber/swagger"
 )
 
-func CreateRouter(r *fiber.App) {
+func CreateRouter(r *fiber.App, h *handler.Handler, verifier *auth.Verifier, tickets *auth.Tickets, userRepo model.UserRepository) {
 	r.Use(logger.New())
 	r.Use(recover.New())
 	r.Use(cors.New())
@@ -22,24 +25,61 @@ func CreateRouter(r *fiber.App) {
 	v1 := api.Group("/v1")
 	v1.Get("/healthcheck", handler.HealthCheck)
 
+	authenticated := middleware.AuthMiddleware(verifier, userRepo)
+
+	// Signing up only needs a valid token, the user record is created by the handler.
 	users := v1.Group("/users")
-	users.Post("/", handler.CreateUser)
-	users.Get("/:id", handler.GetUserByID)
-	users.Put("/:id", handler.UpdateUserByID)
-
-	tasks := v1.Group("/tasks")
-	tasks.Post("/", handler.CreateTask)
-	tasks.Get("/user/:id", handler.GetTasksByUserID)
-	tasks.Get("/:id", handler.GetTaskByID)
-	tasks.Put("/:id", handler.UpdateTaskByID)
-	tasks.Delete("/:id", handler.DeleteTaskByID)
-
-	sessions := v1.Group("/sessions")
-	sessions.Post("/start", handler.StartPomodoroSession)
-	sessions.Post("/end/:id", handler.EndPomodoroSession)
-
-	// stats := v1.Group("/stats", middleware.AuthMiddleware)
-	// stats.Get("/daily", getDailyStats)
-	// stats.Get("/weekly", getWeeklyStats)
-	// stats.Get("/monthly", getMonthlyStats)
+	users.Post("/", middleware.TokenMiddleware(verifier), h.CreateUser)
+	users.Get("/:id", authenticated, h.GetUserByID)
+	users.Put("/:id", authenticated, h.UpdateUserByID)
+	users.Patch("/:id", authenticated, h.PatchUserByID)
+	users.Get("/:id/settings", authenticated, h.GetUserSettings)
+	users.Put("/:id/settings", authenticated, h.UpdateUserSettings)
+
+	tasks := v1.Group("/tasks", authenticated)
+	tasks.Post("/", h.CreateTask)
+	tasks.Post("/bulk", h.BulkUpdateTasks)
+	tasks.Get("/user/:id", h.GetTasksByUserID)
+	tasks.Get("/today", h.GetTodayTasks)
+	tasks.Get("/trash", h.GetTrashTasks)
+	tasks.Get("/:id", h.GetTaskByID)
+	tasks.Put("/:id", h.UpdateTaskByID)
+	tasks.Patch("/:id", h.PatchTaskByID)
+	tasks.Delete("/:id", h.DeleteTaskByID)
+	tasks.Patch("/:id/move", h.MoveTask)
+	tasks.Post("/:id/restore", h.RestoreTaskByID)
+	tasks.Post("/:id/checklist", h.AddChecklistItem)
+	tasks.Put("/:id/checklist/order", h.ReorderChecklist)
+	tasks.Post("/:id/checklist/:itemId/check", h.CheckChecklistItem)
+	tasks.Post("/:id/checklist/:itemId/uncheck", h.UncheckChecklistItem)
+
+	projects := v1.Group("/projects", authenticated)
+	projects.Post("/", h.CreateProject)
+	projects.Get("/", h.GetProjects)
+	projects.Get("/:id", h.GetProjectByID)
+	projects.Put("/:id", h.UpdateProjectByID)
+	projects.Delete("/:id", h.DeleteProjectByID)
+	projects.Get("/:id/rollup", h.GetProjectRollup)
+
+	tags := v1.Group("/tags", authenticated)
+	tags.Get("/", h.GetTags)
+	tags.Post("/rename", h.RenameTag)
+
+	sessions := v1.Group("/sessions", authenticated)
+	sessions.Get("/current", h.GetCurrentPomodoroSession)
+	sessions.Get("/user/:id", h.GetSessionsByUserID)
+	sessions.Post("/start", h.StartPomodoroSession)
+	sessions.Post("/next", h.StartNextPomodoroSession)
+	sessions.Post("/end/:id", h.EndPomodoroSession)
+	sessions.Post("/:id/pause", h.PausePomodoroSession)
+	sessions.Post("/:id/resume", h.ResumePomodoroSession)
+
+	// EventSource cannot send headers, so the stream also accepts a ticket.
+	v1.Post("/events/ticket", authenticated, h.CreateEventTicket)
+	v1.Get("/events", middleware.TicketMiddleware(tickets, userRepo, authenticated), h.StreamSessionEvents)
+
+	stats := v1.Group("/stats", authenticated)
+	stats.Get("/daily", h.GetDailyStats)
+	stats.Get("/weekly", h.GetWeeklyStats)
+	stats.Get("/monthly", h.GetMonthlyStats)
 }